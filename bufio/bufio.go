@@ -24,6 +24,9 @@ var (
 	ErrInvalidUnreadRune = errors.New("bufio: invalid use of UnreadRune")
 	ErrBufferFull        = errors.New("bufio: buffer full")
 	ErrNegativeCount     = errors.New("bufio: negative count")
+	ErrDetached          = errors.New("bufio: use of detached Reader or Writer")
+	ErrNoCheckpoint      = errors.New("bufio: Rollback without Checkpoint")
+	ErrCheckpointFlushed = errors.New("bufio: data written since Checkpoint was already flushed")
 )
 
 // Buffered input.
@@ -36,6 +39,7 @@ type Reader struct {
 	err          error
 	lastByte     int // last byte read for UnreadByte; -1 means invalid
 	lastRuneSize int // size of last rune read for UnreadRune; -1 means invalid
+	stats        *CopyStats
 }
 
 const (
@@ -102,6 +106,31 @@ func (b *Reader) reset(buf []byte, r io.Reader) {
 	}
 }
 
+// Detach hands the underlying io.Reader back to the caller together with
+// a copy of the data that b has buffered but not yet returned, so that the
+// caller can take over the stream, for example after an HTTP Upgrade or
+// STARTTLS. Any pending read error is discarded.
+// Detach 将底层io.Reader连同b已缓冲但尚未返回的数据的副本一起交还给调用者，
+// 使调用者可以接管数据流，例如在HTTP Upgrade或STARTTLS之后。任何待返回的读取错误都会被丢弃。
+//
+// After Detach, every read from b fails with ErrDetached until b is Reset.
+// Detach 之后，b上的所有读取都将以ErrDetached失败，直到调用Reset。
+func (b *Reader) Detach() (rd io.Reader, buffered []byte) {
+	rd = b.rd
+	buffered = bytes.Clone(b.buf[b.r:b.w])
+	b.reset(b.buf, detachedReader{})
+	return rd, buffered
+}
+
+// detachedReader is the underlying reader of a detached Reader.
+// Returning the error from Read, rather than storing it in Reader.err,
+// makes it sticky: Reader clears err once it has been returned.
+// detachedReader 是已分离的Reader的底层读取器。
+// 从Read返回错误而不是存入Reader.err，可以使错误保持粘滞：Reader在返回err后会将其清除。
+type detachedReader struct{}
+
+func (detachedReader) Read([]byte) (int, error) { return 0, ErrDetached }
+
 var errNegativeRead = errors.New("bufio: reader returned negative count from Read")
 
 // fill reads a new chunk into the buffer.
@@ -629,23 +658,35 @@ func (b *Reader) ReadString(delim byte) (string, error) {
 // WriteTo 实现了io.WriterTo。
 // 这可能会多次调用底层Reader的Read方法。
 // 如果底层读取器支持WriteTo方法，则调用底层WriteTo而不缓冲。
+//
+// Any buffered data is written first; the rest of the transfer is then
+// handed to the WriteTo method of the underlying reader or the ReadFrom
+// method of w, if either exists, so that copies between files, pipes and
+// sockets can use the operating system's zero-copy paths such as
+// sendfile and splice. See SetCopyStats.
+// 首先写出已缓冲的数据；如果底层读取器有WriteTo方法或者w有ReadFrom方法，
+// 剩余的传输就交给它们，这样文件、管道和套接字之间的拷贝可以使用操作系统的零拷贝路径，例如sendfile和splice。
+// 参见SetCopyStats。
 func (b *Reader) WriteTo(w io.Writer) (n int64, err error) {
 	b.lastByte = -1
 	b.lastRuneSize = -1
 
 	n, err = b.writeBuf(w)
+	b.stats.count(copyBuffered, n)
 	if err != nil {
 		return
 	}
 
 	if r, ok := b.rd.(io.WriterTo); ok {
 		m, err := r.WriteTo(w)
+		b.stats.count(copyWriterTo, m)
 		n += m
 		return n, err
 	}
 
 	if w, ok := w.(io.ReaderFrom); ok {
 		m, err := w.ReadFrom(b.rd)
+		b.stats.count(copyReaderFrom, m)
 		n += m
 		return n, err
 	}
@@ -658,6 +699,7 @@ func (b *Reader) WriteTo(w io.Writer) (n int64, err error) {
 	for b.r < b.w {
 		// b.r < b.w => buffer is not empty
 		m, err := b.writeBuf(w)
+		b.stats.count(copyBuffered, m)
 		n += m
 		if err != nil {
 			return n, err
@@ -697,12 +739,19 @@ func (b *Reader) writeBuf(w io.Writer) (int64, error) {
 // the underlying io.Writer.
 // 写入所有数据后，客户端应调用Flush方法，以确保所有数据都已转发到底层io.Writer。
 type Writer struct {
-	err error
-	buf []byte
-	n   int
-	wr  io.Writer
+	err   error
+	buf   []byte
+	n     int
+	wr    io.Writer
+	mark  int // buf offset of the checkpoint, or noCheckpoint or lostCheckpoint
+	stats *CopyStats
 }
 
+const (
+	noCheckpoint   = -1 // Checkpoint has not been called
+	lostCheckpoint = -2 // data written since the checkpoint left the buffer
+)
+
 // NewWriterSize returns a new Writer whose buffer has at least the specified
 // size. If the argument io.Writer is already a Writer with large enough
 // size, it returns the underlying Writer.
@@ -719,8 +768,9 @@ func NewWriterSize(w io.Writer, size int) *Writer {
 		size = defaultBufSize
 	}
 	return &Writer{
-		buf: make([]byte, size),
-		wr:  w,
+		buf:  make([]byte, size),
+		wr:   w,
+		mark: noCheckpoint,
 	}
 }
 
@@ -758,6 +808,27 @@ func (b *Writer) Reset(w io.Writer) {
 	b.err = nil
 	b.n = 0
 	b.wr = w
+	b.mark = noCheckpoint
+	b.stats = nil
+}
+
+// Detach hands the underlying io.Writer back to the caller together with
+// a copy of the data that has been written to b but not yet flushed, so
+// that the caller can take over the stream. Any write error is discarded.
+// Detach 将底层io.Writer连同已写入b但尚未刷新的数据的副本一起交还给调用者，
+// 使调用者可以接管数据流。任何写入错误都会被丢弃。
+//
+// After Detach, every write to b and Flush fail with ErrDetached until b
+// is Reset.
+// Detach 之后，b上的所有写入和Flush都将以ErrDetached失败，直到调用Reset。
+func (b *Writer) Detach() (wr io.Writer, unflushed []byte) {
+	wr = b.wr
+	unflushed = bytes.Clone(b.buf[:b.n])
+	b.n = 0
+	b.wr = nil
+	b.err = ErrDetached
+	b.mark = noCheckpoint
+	return wr, unflushed
 }
 
 // Flush writes any buffered data to the underlying io.Writer.
@@ -773,6 +844,7 @@ func (b *Writer) Flush() error {
 	if n < b.n && err == nil {
 		err = io.ErrShortWrite
 	}
+	b.wrote(int64(n))
 	if err != nil {
 		if n > 0 && n < b.n {
 			copy(b.buf[0:b.n-n], b.buf[n:b.n])
@@ -785,6 +857,59 @@ func (b *Writer) Flush() error {
 	return nil
 }
 
+// wrote records that n bytes were written to the underlying io.Writer,
+// either from the start of buf or bypassing it while buf was empty.
+// wrote 记录有n个字节被写入了底层io.Writer，它们要么来自buf的开头，要么是在buf为空时绕过buf直接写入的。
+func (b *Writer) wrote(n int64) {
+	if b.mark < 0 || n <= 0 {
+		return
+	}
+	if n > int64(b.mark) {
+		// Data written after the checkpoint is gone.
+		// 检查点之后写入的数据已经被写出
+		b.mark = lostCheckpoint
+		return
+	}
+	b.mark -= int(n)
+}
+
+// Checkpoint records the current end of the buffered data, so that a
+// later Rollback can discard what is written after it. Each call replaces
+// the previous checkpoint.
+// Checkpoint 记录缓冲数据当前的结尾位置，以便之后调用Rollback丢弃在此之后写入的数据。
+// 每次调用都会替换之前的检查点。
+func (b *Writer) Checkpoint() {
+	b.mark = b.n
+}
+
+// Rollback discards the data written to b since the last call to
+// Checkpoint, such as a record that an encoder failed to complete. The
+// checkpoint stays in place, so Rollback may be called again later.
+// Rollback 丢弃自上次调用Checkpoint以来写入b的数据，例如编码器未能写完的一条记录。
+// 检查点会保留，因此之后可以再次调用Rollback。
+//
+// Rollback is only possible while all of that data is still buffered.
+// If some of it has already been written to the underlying io.Writer,
+// by Flush or by a write too large for the buffer, Rollback returns
+// ErrCheckpointFlushed and leaves the buffer unchanged. Without a
+// checkpoint it returns ErrNoCheckpoint, and after a write error it
+// returns that error.
+// 只有当这些数据仍全部在缓冲区中时才能回滚。如果其中一部分已经被Flush或者因超出缓冲区大小的写入而写入了底层io.Writer，
+// Rollback返回ErrCheckpointFlushed并保持缓冲区不变。没有检查点时返回ErrNoCheckpoint，发生写入错误之后返回该错误。
+func (b *Writer) Rollback() error {
+	if b.err != nil {
+		return b.err
+	}
+	switch b.mark {
+	case noCheckpoint:
+		return ErrNoCheckpoint
+	case lostCheckpoint:
+		return ErrCheckpointFlushed
+	}
+	b.n = b.mark
+	return nil
+}
+
 // Available returns how many bytes are unused in the buffer.
 // Available 返回缓冲区中未使用的字节数。
 func (b *Writer) Available() int { return len(b.buf) - b.n }
@@ -818,6 +943,7 @@ func (b *Writer) Write(p []byte) (nn int, err error) {
 			// Large write, empty buffer.
 			// Write directly from p to avoid copy.
 			n, b.err = b.wr.Write(p)
+			b.wrote(int64(n))
 		} else {
 			n = copy(b.buf[b.n:], p)
 			b.n += n
@@ -906,6 +1032,7 @@ func (b *Writer) WriteString(s string) (int, error) {
 			// 大写，空缓冲区，底层写入器支持WriteString：将写入转发到底层StringWriter。
 			// 这避免了额外的复制。
 			n, b.err = sw.WriteString(s)
+			b.wrote(int64(n))
 		} else {
 			n = copy(b.buf[b.n:], s)
 			b.n += n
@@ -925,15 +1052,28 @@ func (b *Writer) WriteString(s string) (int, error) {
 
 // ReadFrom implements io.ReaderFrom. If the underlying writer
 // supports the ReadFrom method, this calls the underlying ReadFrom.
-// If there is buffered data and an underlying ReadFrom, this fills
-// the buffer and writes it before calling ReadFrom.
+// If there is buffered data and an underlying ReadFrom, this flushes
+// the buffer before calling ReadFrom, so that the whole transfer from r
+// can use the operating system's zero-copy paths such as sendfile and
+// splice. See SetCopyStats.
 // ReadFrom 实现了io.ReaderFrom。 如果底层写入器支持ReadFrom方法，则调用底层ReadFrom。
-// 如果有缓冲数据和底层ReadFrom，则在调用ReadFrom之前填充缓冲区并将其写入。
+// 如果有缓冲数据和底层ReadFrom，则在调用ReadFrom之前先刷新缓冲区，
+// 这样从r开始的整个传输都可以使用操作系统的零拷贝路径，例如sendfile和splice。参见SetCopyStats。
 func (b *Writer) ReadFrom(r io.Reader) (n int64, err error) {
 	if b.err != nil {
 		return 0, b.err
 	}
-	readerFrom, readerFromOK := b.wr.(io.ReaderFrom)
+	if readerFrom, ok := b.wr.(io.ReaderFrom); ok {
+		// 如果底层写入器支持ReadFrom方法，先刷新缓冲区，然后直接调用底层的ReadFrom方法
+		if err := b.Flush(); err != nil {
+			return 0, err
+		}
+		n, err = readerFrom.ReadFrom(r)
+		b.stats.count(copyReaderFrom, n)
+		b.wrote(n)
+		b.err = err
+		return n, err
+	}
 	var m int
 	for {
 		if b.Available() == 0 {
@@ -941,13 +1081,6 @@ func (b *Writer) ReadFrom(r io.Reader) (n int64, err error) {
 				return n, err1
 			}
 		}
-		if readerFromOK && b.Buffered() == 0 {
-			// 如果底层写入器支持ReadFrom方法，并且缓冲区为空，直接调用底层的ReadFrom方法
-			nn, err := readerFrom.ReadFrom(r)
-			b.err = err
-			n += nn
-			return n, err
-		}
 		nr := 0
 		for nr < maxConsecutiveEmptyReads {
 			m, err = r.Read(b.buf[b.n:])
@@ -962,6 +1095,7 @@ func (b *Writer) ReadFrom(r io.Reader) (n int64, err error) {
 		}
 		b.n += m
 		n += int64(m)
+		b.stats.count(copyBuffered, int64(m))
 		if err != nil {
 			break
 		}
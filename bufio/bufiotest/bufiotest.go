@@ -0,0 +1,176 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bufiotest provides misbehaving io.Reader and io.Writer
+// implementations for testing buffered I/O: streams that deliver data in
+// odd-sized chunks, return (0, nil), report negative counts, write short,
+// or fail after a given number of bytes.
+//
+// A Reader and a Writer are configured through their fields, for example
+//
+//	r := &bufiotest.Reader{
+//		R:         strings.NewReader(input),
+//		Chunks:    []int{1, 0, 7}, // 1 byte, then (0, nil), then 7 bytes, ...
+//		Err:       bufiotest.ErrInjected,
+//		FailAfter: 100,
+//	}
+//	br := bufio.NewReader(r)
+package bufiotest
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrInjected is a convenient error to inject as the Err of a Reader or
+// Writer.
+// ErrInjected 是一个便于作为Reader或Writer的Err注入的错误。
+var ErrInjected = errors.New("bufiotest: injected fault")
+
+// Reader is an io.Reader that delivers the data of R and injects faults
+// into the stream as configured by its fields. The zero value, apart from
+// R, passes reads through unchanged.
+// Reader 是一个io.Reader，它传递R的数据，并按其字段的配置向数据流中注入故障。
+// 除了R以外都是零值的Reader会原样传递读取操作。
+type Reader struct {
+	// R is the source of the data. A nil R is an empty stream.
+	// R 是数据的来源。nil的R表示空数据流。
+	R io.Reader
+
+	// Chunks lists the largest number of bytes delivered by successive
+	// calls to Read; the list repeats. A Read whose entry is zero or
+	// negative returns (0, nil) without reading from R. An empty list
+	// puts no limit on reads.
+	// Chunks 列出依次调用Read时最多传递的字节数；列表循环使用。
+	// 对应项为零或负数的Read返回(0, nil)，而不从R读取。空列表不限制读取。
+	Chunks []int
+
+	// Once FailAfter bytes have been delivered, Read stops reading from
+	// R and returns Negative and Err instead, on this and every later
+	// call. Setting Err to nil and Negative to a negative count makes
+	// Read report a count that is invalid for an io.Reader. If both are
+	// zero, no fault is injected.
+	// 当已经传递了FailAfter个字节后，本次及之后的每次Read调用都不再从R读取，而是返回Negative和Err。
+	// 将Err设置为nil、Negative设置为负数会使Read报告一个对于io.Reader无效的字节数。
+	// 如果两者都为零值，则不注入故障。
+	FailAfter int64
+	Err       error
+	Negative  int
+
+	// ErrWithData makes the Read that delivers the last byte before the
+	// failure point return Err along with the data, as io.Reader permits,
+	// rather than on the next call.
+	// ErrWithData 使传递失败点之前最后一个字节的Read调用将Err与数据一起返回（io.Reader允许这样做），
+	// 而不是在下一次调用时返回。
+	ErrWithData bool
+
+	n int64 // bytes delivered so far
+	i int   // index of the next entry in Chunks
+}
+
+// faulty reports whether r injects a fault.
+// faulty 报告r是否会注入故障。
+func (r *Reader) faulty() bool {
+	return r.Err != nil || r.Negative != 0
+}
+
+// Read reads up to len(p) bytes into p, subject to the faults configured
+// in r.
+// Read 读取最多len(p)个字节到p中，受r中配置的故障影响。
+func (r *Reader) Read(p []byte) (int, error) {
+	limit := len(p)
+	if r.faulty() {
+		left := r.FailAfter - r.n
+		if left <= 0 {
+			return r.Negative, r.Err
+		}
+		limit = int(min(int64(limit), left))
+	}
+	if len(r.Chunks) > 0 {
+		c := r.Chunks[r.i%len(r.Chunks)]
+		r.i++
+		if c <= 0 {
+			return 0, nil
+		}
+		limit = min(limit, c)
+	}
+	if r.R == nil {
+		return 0, io.EOF
+	}
+	n, err := r.R.Read(p[:limit])
+	r.n += int64(n)
+	if err == nil && r.ErrWithData && r.Err != nil && r.n >= r.FailAfter {
+		err = r.Err
+	}
+	return n, err
+}
+
+// Delivered returns the number of bytes delivered by r so far.
+// Delivered 返回r到目前为止已传递的字节数。
+func (r *Reader) Delivered() int64 { return r.n }
+
+// Writer is an io.Writer that passes data to W and injects faults as
+// configured by its fields. The zero value, apart from W, passes writes
+// through unchanged.
+// Writer 是一个io.Writer，它将数据传递给W，并按其字段的配置注入故障。
+// 除了W以外都是零值的Writer会原样传递写入操作。
+type Writer struct {
+	// W receives the bytes accepted by Write. A nil W discards them.
+	// W 接收Write接受的字节。nil的W会丢弃它们。
+	W io.Writer
+
+	// Chunks lists the largest number of bytes accepted by successive
+	// calls to Write; the list repeats. A Write that accepts fewer bytes
+	// than it was given returns a nil error, which is a short write; an
+	// entry that is zero or negative makes Write return (0, nil). An
+	// empty list puts no limit on writes.
+	// Chunks 列出依次调用Write时最多接受的字节数；列表循环使用。接受的字节数少于给定字节数的Write返回nil错误，
+	// 即短写；为零或负数的项使Write返回(0, nil)。空列表不限制写入。
+	Chunks []int
+
+	// If Err is not nil, Write accepts at most FailAfter bytes in total.
+	// The Write that would go past that point accepts the bytes before it
+	// and returns their count with Err, and every later Write returns
+	// (0, Err).
+	// 如果Err不为nil，Write总共最多接受FailAfter个字节。将要越过该点的Write调用接受该点之前的字节，
+	// 并返回其数量和Err，之后的每次Write调用都返回(0, Err)。
+	FailAfter int64
+	Err       error
+
+	n int64 // bytes accepted so far
+	i int   // index of the next entry in Chunks
+}
+
+// Write writes some or all of p to w.W, subject to the faults configured
+// in w.
+// Write 将p的部分或全部写入w.W，受w中配置的故障影响。
+func (w *Writer) Write(p []byte) (int, error) {
+	limit := len(p)
+	if len(w.Chunks) > 0 {
+		c := w.Chunks[w.i%len(w.Chunks)]
+		w.i++
+		limit = min(limit, max(c, 0))
+	}
+	var fault error
+	if w.Err != nil {
+		if left := w.FailAfter - w.n; left <= 0 || left < int64(limit) {
+			limit = int(max(left, 0))
+			fault = w.Err
+		}
+	}
+	n := limit
+	var err error
+	if w.W != nil {
+		n, err = w.W.Write(p[:limit])
+	}
+	w.n += int64(n)
+	if err == nil {
+		err = fault
+	}
+	return n, err
+}
+
+// Accepted returns the number of bytes accepted by w so far.
+// Accepted 返回w到目前为止已接受的字节数。
+func (w *Writer) Accepted() int64 { return w.n }
@@ -0,0 +1,213 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufiotest_test
+
+import (
+	"bufio"
+	. "bufio/bufiotest"
+	"io"
+	"strings"
+	"testing"
+)
+
+type readResult struct {
+	data string
+	n    int // used instead of len(data) if negative
+	err  error
+}
+
+var readerTests = []struct {
+	name  string
+	r     Reader // R is set to "hello, world"
+	reads []int  // len(p) of successive reads
+	want  []readResult
+}{
+	{
+		"pass through",
+		Reader{},
+		[]int{5, 100, 1},
+		[]readResult{{"hello", 0, nil}, {", world", 0, nil}, {"", 0, io.EOF}},
+	},
+	{
+		"chunks cycle",
+		Reader{Chunks: []int{1, 3}},
+		[]int{10, 10, 10, 10, 10},
+		[]readResult{{"h", 0, nil}, {"ell", 0, nil}, {"o", 0, nil}, {", w", 0, nil}, {"o", 0, nil}},
+	},
+	{
+		"chunk smaller than p only",
+		Reader{Chunks: []int{4}},
+		[]int{2, 10},
+		[]readResult{{"he", 0, nil}, {"llo,", 0, nil}},
+	},
+	{
+		"empty reads",
+		Reader{Chunks: []int{0, 2, -1}},
+		[]int{10, 10, 10, 10},
+		[]readResult{{"", 0, nil}, {"he", 0, nil}, {"", 0, nil}, {"", 0, nil}},
+	},
+	{
+		"fail after",
+		Reader{FailAfter: 7, Err: ErrInjected},
+		[]int{5, 5, 5, 5},
+		[]readResult{{"hello", 0, nil}, {", ", 0, nil}, {"", 0, ErrInjected}, {"", 0, ErrInjected}},
+	},
+	{
+		"fail at start",
+		Reader{Err: ErrInjected},
+		[]int{5, 5},
+		[]readResult{{"", 0, ErrInjected}, {"", 0, ErrInjected}},
+	},
+	{
+		"fail with data",
+		Reader{FailAfter: 7, Err: ErrInjected, ErrWithData: true},
+		[]int{5, 5, 5},
+		[]readResult{{"hello", 0, nil}, {", ", 0, ErrInjected}, {"", 0, ErrInjected}},
+	},
+	{
+		"fail with data needs the last byte",
+		Reader{Chunks: []int{1}, FailAfter: 2, Err: ErrInjected, ErrWithData: true},
+		[]int{5, 5, 5},
+		[]readResult{{"h", 0, nil}, {"e", 0, ErrInjected}, {"", 0, ErrInjected}},
+	},
+	{
+		"negative count",
+		Reader{FailAfter: 3, Negative: -1},
+		[]int{5, 5},
+		[]readResult{{"hel", 0, nil}, {"", -1, nil}},
+	},
+	{
+		"negative count with error",
+		Reader{Negative: -2, Err: ErrInjected},
+		[]int{5},
+		[]readResult{{"", -2, ErrInjected}},
+	},
+}
+
+func TestReader(t *testing.T) {
+	for _, tt := range readerTests {
+		r := tt.r
+		r.R = strings.NewReader("hello, world")
+		delivered := 0
+		for i, size := range tt.reads {
+			p := make([]byte, size)
+			n, err := r.Read(p)
+			want := tt.want[i]
+			wantN := len(want.data)
+			if want.n < 0 {
+				wantN = want.n
+			}
+			if n != wantN || err != want.err || (n > 0 && string(p[:n]) != want.data) {
+				t.Fatalf("%s: Read %d = %d, %v, want %q, %v", tt.name, i, n, err, want.data, want.err)
+			}
+			delivered += len(want.data)
+		}
+		if r.Delivered() != int64(delivered) {
+			t.Errorf("%s: Delivered = %d, want %d", tt.name, r.Delivered(), delivered)
+		}
+	}
+}
+
+func TestReaderNilR(t *testing.T) {
+	var r Reader
+	if n, err := r.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Fatalf("Read = %d, %v, want 0, io.EOF", n, err)
+	}
+}
+
+type writeResult struct {
+	n   int
+	err error
+}
+
+var writerTests = []struct {
+	name   string
+	w      Writer
+	writes []string
+	want   []writeResult
+	out    string
+}{
+	{
+		"pass through",
+		Writer{},
+		[]string{"hello", ", world"},
+		[]writeResult{{5, nil}, {7, nil}},
+		"hello, world",
+	},
+	{
+		"short writes",
+		Writer{Chunks: []int{3, 0, 10}},
+		[]string{"hello", "lo", "lo, world"},
+		[]writeResult{{3, nil}, {0, nil}, {9, nil}},
+		"hello, world",
+	},
+	{
+		"fail after",
+		Writer{FailAfter: 7, Err: ErrInjected},
+		[]string{"hello", ", world", "x"},
+		[]writeResult{{5, nil}, {2, ErrInjected}, {0, ErrInjected}},
+		"hello, ",
+	},
+	{
+		"fail at the boundary",
+		Writer{FailAfter: 5, Err: ErrInjected},
+		[]string{"hello", ", world"},
+		[]writeResult{{5, nil}, {0, ErrInjected}},
+		"hello",
+	},
+	{
+		"short write before failure",
+		Writer{Chunks: []int{2}, FailAfter: 3, Err: ErrInjected},
+		[]string{"hello", "llo"},
+		[]writeResult{{2, nil}, {1, ErrInjected}},
+		"hel",
+	},
+}
+
+func TestWriter(t *testing.T) {
+	for _, tt := range writerTests {
+		var out strings.Builder
+		w := tt.w
+		w.W = &out
+		for i, s := range tt.writes {
+			n, err := w.Write([]byte(s))
+			if n != tt.want[i].n || err != tt.want[i].err {
+				t.Fatalf("%s: Write(%q) = %d, %v, want %d, %v", tt.name, s, n, err, tt.want[i].n, tt.want[i].err)
+			}
+		}
+		if out.String() != tt.out || w.Accepted() != int64(len(tt.out)) {
+			t.Errorf("%s: wrote %q, Accepted = %d, want %q", tt.name, out.String(), w.Accepted(), tt.out)
+		}
+	}
+}
+
+// TestWithBufio checks the faults as seen through bufio.
+func TestWithBufio(t *testing.T) {
+	r := &Reader{R: strings.NewReader("hello, world"), Chunks: []int{1, 0, 3}, FailAfter: 7, Err: ErrInjected}
+	got, err := io.ReadAll(bufio.NewReaderSize(r, 16))
+	if string(got) != "hello, " || err != ErrInjected {
+		t.Errorf("ReadAll = %q, %v, want %q, %v", got, err, "hello, ", ErrInjected)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("negative count did not make bufio.Reader panic")
+			}
+		}()
+		bufio.NewReader(&Reader{Negative: -1}).ReadByte()
+	}()
+
+	if _, err := bufio.NewReader(&Reader{R: strings.NewReader("x"), Chunks: []int{0}}).ReadByte(); err != io.ErrNoProgress {
+		t.Errorf("ReadByte with only empty reads = %v, want io.ErrNoProgress", err)
+	}
+
+	var out strings.Builder
+	bw := bufio.NewWriterSize(&Writer{W: &out, Chunks: []int{3}}, 16)
+	bw.WriteString("abcdef")
+	if err := bw.Flush(); err != io.ErrShortWrite || out.String() != "abc" || bw.Buffered() != 3 {
+		t.Errorf("Flush with short write = %v, wrote %q, %d buffered", err, out.String(), bw.Buffered())
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"bufio/bufiotest"
+	"io"
+	"strings"
+	"testing"
+)
+
+// The Writers of rollbackTests have a buffer of 16 bytes.
+var rollbackTests = []struct {
+	name string
+	run  func(w *Writer)
+	err  error  // of Rollback
+	out  string // written after a final Flush
+}{
+	{
+		"no checkpoint",
+		func(w *Writer) { w.WriteString("abc") },
+		ErrNoCheckpoint,
+		"abc",
+	},
+	{
+		"buffered",
+		func(w *Writer) {
+			w.WriteString("keep")
+			w.Checkpoint()
+			w.WriteString("drop")
+		},
+		nil,
+		"keep",
+	},
+	{
+		"latest checkpoint",
+		func(w *Writer) {
+			w.Checkpoint()
+			w.WriteString("keep")
+			w.Checkpoint()
+			w.WriteString("drop")
+		},
+		nil,
+		"keep",
+	},
+	{
+		"flushed before the checkpoint",
+		func(w *Writer) {
+			w.WriteString("pre")
+			w.Checkpoint()
+			w.Flush()
+			w.WriteString("post")
+		},
+		nil,
+		"pre",
+	},
+	{
+		"flushed between checkpoint and rollback",
+		func(w *Writer) {
+			w.WriteString("pre")
+			w.Checkpoint()
+			w.WriteString("post")
+			w.Flush()
+		},
+		ErrCheckpointFlushed,
+		"prepost",
+	},
+	{
+		"flushed by a full buffer",
+		func(w *Writer) {
+			w.Checkpoint()
+			w.WriteString("0123456789abcdef")
+			w.WriteString("X")
+		},
+		ErrCheckpointFlushed,
+		"0123456789abcdefX",
+	},
+	{
+		"large write",
+		func(w *Writer) {
+			w.WriteString("abcd")
+			w.Checkpoint()
+			w.WriteString(strings.Repeat("x", 40))
+		},
+		ErrCheckpointFlushed,
+		"abcd" + strings.Repeat("x", 40),
+	},
+}
+
+func TestWriterRollback(t *testing.T) {
+	for _, tt := range rollbackTests {
+		var out strings.Builder
+		w := NewWriterSize(&out, 16)
+		tt.run(w)
+		buffered := w.Buffered()
+		if err := w.Rollback(); err != tt.err {
+			t.Errorf("%s: Rollback = %v, want %v", tt.name, err, tt.err)
+		}
+		// A failed Rollback leaves the buffer unchanged.
+		if tt.err != nil && w.Buffered() != buffered {
+			t.Errorf("%s: failed Rollback changed Buffered from %d to %d", tt.name, buffered, w.Buffered())
+		}
+		if err := w.Flush(); err != nil || out.String() != tt.out {
+			t.Errorf("%s: Flush = %v, wrote %q, want %q", tt.name, err, out.String(), tt.out)
+		}
+	}
+}
+
+// TestWriterRollbackAgain checks that the checkpoint stays in place after
+// Rollback and is dropped by Reset.
+func TestWriterRollbackAgain(t *testing.T) {
+	var out strings.Builder
+	w := NewWriterSize(&out, 16)
+	w.WriteString("a")
+	w.Checkpoint()
+	for _, s := range []string{"b", "cd"} {
+		w.WriteString(s)
+		if err := w.Rollback(); err != nil || w.Buffered() != 1 {
+			t.Fatalf("Rollback after %q = %v with %d buffered", s, err, w.Buffered())
+		}
+	}
+	w.Reset(&out)
+	if err := w.Rollback(); err != ErrNoCheckpoint {
+		t.Fatalf("Rollback after Reset = %v, want %v", err, ErrNoCheckpoint)
+	}
+}
+
+func TestWriterRollbackWriteError(t *testing.T) {
+	var out strings.Builder
+	w := NewWriterSize(&bufiotest.Writer{W: &out, Chunks: []int{2}}, 16)
+	w.WriteString("abcd")
+	w.Checkpoint()
+	w.WriteString("ef")
+	if err := w.Flush(); err != io.ErrShortWrite {
+		t.Fatalf("Flush = %v, want %v", err, io.ErrShortWrite)
+	}
+	if err := w.Rollback(); err != io.ErrShortWrite {
+		t.Fatalf("Rollback after write error = %v, want %v", err, io.ErrShortWrite)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+// Conn is a buffered net.Conn. Like ReadWriter it stores pointers to a
+// Reader and a Writer, and in addition it forwards the deadline and
+// address methods to the underlying connection, so it implements net.Conn.
+// Conn 是带缓冲的net.Conn。和ReadWriter一样，它存储了指向Reader和Writer的指针，
+// 此外它还将设置超时时间和地址相关的方法转发给底层连接，因此它实现了net.Conn。
+//
+// A read or write that fails because a deadline expired does not break the
+// Conn: data that was already buffered stays in the Reader, unflushed data
+// stays in the Writer, and once the deadline has been extended with one of
+// the Set*Deadline methods, reading and flushing continue where they
+// stopped. Other errors remain sticky as usual.
+// 因超时时间到期而失败的读或写不会破坏Conn：已经缓冲的数据保留在Reader中，未刷新的数据保留在Writer中，
+// 一旦通过Set*Deadline方法延长了超时时间，读取和刷新就会从中断的地方继续。其他错误照常保持粘滞。
+type Conn struct {
+	*Reader
+	*Writer
+	conn net.Conn
+}
+
+// NewConnSize returns a new Conn for c whose Reader and Writer have at
+// least the specified buffer sizes.
+// NewConnSize 返回一个基于c的Conn，其Reader和Writer的缓冲区至少具有指定的大小。
+func NewConnSize(c net.Conn, readSize, writeSize int) *Conn {
+	return &Conn{
+		Reader: NewReaderSize(c, readSize),
+		Writer: NewWriterSize(c, writeSize),
+		conn:   c,
+	}
+}
+
+// NewConn returns a new Conn for c whose Reader and Writer have the
+// default buffer size.
+// NewConn 返回一个基于c的Conn，其Reader和Writer的缓冲区具有默认大小。
+func NewConn(c net.Conn) *Conn {
+	return NewConnSize(c, defaultBufSize, defaultBufSize)
+}
+
+// Read reads data into p; see Reader.Read.
+// Read 将数据读入p；参见Reader.Read。
+func (c *Conn) Read(p []byte) (n int, err error) { return c.Reader.Read(p) }
+
+// Write writes the contents of p into the buffer; see Writer.Write.
+// Write 将p的内容写入缓冲区；参见Writer.Write。
+func (c *Conn) Write(p []byte) (nn int, err error) { return c.Writer.Write(p) }
+
+// Close closes the underlying connection without flushing; call Flush
+// first to send any buffered data.
+// Close 关闭底层连接但不刷新缓冲区；需要先调用Flush发送缓冲的数据。
+func (c *Conn) Close() error { return c.conn.Close() }
+
+// LocalAddr returns the local network address of the underlying connection.
+// LocalAddr 返回底层连接的本地网络地址。
+func (c *Conn) LocalAddr() net.Addr { return c.conn.LocalAddr() }
+
+// RemoteAddr returns the remote network address of the underlying connection.
+// RemoteAddr 返回底层连接的远端网络地址。
+func (c *Conn) RemoteAddr() net.Addr { return c.conn.RemoteAddr() }
+
+// SetDeadline sets the read and write deadlines of the underlying
+// connection. It clears a timeout recorded by an earlier read or write,
+// so buffered data becomes usable again.
+// SetDeadline 设置底层连接的读写超时时间。它会清除之前读或写记录的超时错误，使缓冲的数据重新可用。
+func (c *Conn) SetDeadline(t time.Time) error {
+	if err := c.conn.SetDeadline(t); err != nil {
+		return err
+	}
+	c.Reader.clearTimeout()
+	c.Writer.clearTimeout()
+	return nil
+}
+
+// SetReadDeadline sets the read deadline of the underlying connection and
+// clears a timeout recorded by an earlier read.
+// SetReadDeadline 设置底层连接的读超时时间，并清除之前读取记录的超时错误。
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	if err := c.conn.SetReadDeadline(t); err != nil {
+		return err
+	}
+	c.Reader.clearTimeout()
+	return nil
+}
+
+// SetWriteDeadline sets the write deadline of the underlying connection
+// and clears a timeout recorded by an earlier write, so that Flush sends
+// the data that is still buffered.
+// SetWriteDeadline 设置底层连接的写超时时间，并清除之前写入记录的超时错误，使Flush能够发送仍在缓冲区中的数据。
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	if err := c.conn.SetWriteDeadline(t); err != nil {
+		return err
+	}
+	c.Writer.clearTimeout()
+	return nil
+}
+
+// isTimeout reports whether err is a timeout, such as an expired deadline.
+// isTimeout 报告err是否为超时错误，例如超时时间到期。
+func isTimeout(err error) bool {
+	var t interface{ Timeout() bool }
+	return errors.As(err, &t) && t.Timeout()
+}
+
+// clearTimeout drops a pending timeout error. The bytes in buf[r:w]
+// are unaffected.
+// clearTimeout 丢弃尚未返回的超时错误。buf[r:w]中的字节不受影响。
+func (b *Reader) clearTimeout() {
+	if isTimeout(b.err) {
+		b.err = nil
+	}
+}
+
+// clearTimeout drops a timeout error recorded by Flush. Flush keeps the
+// bytes it could not write in buf[:n], so the next Flush retries them.
+// clearTimeout 丢弃Flush记录的超时错误。Flush会将没写出去的字节保留在buf[:n]中，因此下一次Flush会重试它们。
+func (b *Writer) clearTimeout() {
+	if isTimeout(b.err) {
+		b.err = nil
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+var _ net.Conn = (*Conn)(nil)
+
+var connReadDeadlineTests = []struct {
+	name string
+	set  func(c *Conn, t time.Time) error
+}{
+	{"SetDeadline", (*Conn).SetDeadline},
+	{"SetReadDeadline", (*Conn).SetReadDeadline},
+}
+
+// TestConnReadTimeout checks that a Conn keeps the data it buffered before
+// a read deadline expired, and reads on once the deadline is extended.
+func TestConnReadTimeout(t *testing.T) {
+	for _, tt := range connReadDeadlineTests {
+		a, b := net.Pipe()
+		c := NewConnSize(a, 16, 16)
+		go b.Write([]byte("ab"))
+
+		tt.set(c, time.Now().Add(20*time.Millisecond))
+		if p, err := c.Peek(3); string(p) != "ab" || !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Fatalf("%s: Peek past deadline = %q, %v", tt.name, p, err)
+		}
+		// Once the deadline is extended, reading continues with the
+		// buffered data.
+		tt.set(c, time.Time{})
+		go b.Write([]byte("c\n"))
+		if s, err := c.ReadString('\n'); s != "abc\n" || err != nil {
+			t.Fatalf("%s: ReadString after new deadline = %q, %v, want %q, nil", tt.name, s, err, "abc\n")
+		}
+		a.Close()
+		b.Close()
+	}
+}
+
+var connWriteDeadlineTests = []struct {
+	name string
+	set  func(c *Conn, t time.Time) error
+}{
+	{"SetDeadline", (*Conn).SetDeadline},
+	{"SetWriteDeadline", (*Conn).SetWriteDeadline},
+}
+
+// TestConnWriteTimeout checks that a Conn keeps the data a Flush could not
+// send before the write deadline expired, and sends it on the next Flush
+// once the deadline is extended.
+func TestConnWriteTimeout(t *testing.T) {
+	for _, tt := range connWriteDeadlineTests {
+		a, b := net.Pipe()
+		c := NewConnSize(a, 16, 16)
+		c.WriteString("hello")
+		tt.set(c, time.Now().Add(10*time.Millisecond))
+		if err := c.Flush(); !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Fatalf("%s: Flush past deadline = %v", tt.name, err)
+		}
+		if n := c.Writer.Buffered(); n != 5 {
+			t.Fatalf("%s: Buffered after timeout = %d, want 5", tt.name, n)
+		}
+		// The timeout is sticky until the deadline is changed.
+		if err := c.Flush(); !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Fatalf("%s: second Flush = %v, want the timeout", tt.name, err)
+		}
+
+		tt.set(c, time.Time{})
+		done := make(chan string)
+		go func() {
+			buf := make([]byte, 5)
+			io.ReadFull(b, buf)
+			done <- string(buf)
+		}()
+		if err := c.Flush(); err != nil {
+			t.Fatalf("%s: Flush after new deadline = %v", tt.name, err)
+		}
+		if s := <-done; s != "hello" {
+			t.Fatalf("%s: peer read %q, want %q", tt.name, s, "hello")
+		}
+		a.Close()
+		b.Close()
+	}
+}
+
+func TestConnReadDeadlineKeepsWriteTimeout(t *testing.T) {
+	a, b := net.Pipe()
+	defer b.Close()
+	c := NewConn(a)
+	defer c.Close()
+	c.WriteString("x")
+	c.SetWriteDeadline(time.Now().Add(time.Millisecond))
+	c.Flush()
+	c.SetReadDeadline(time.Time{})
+	if err := c.Flush(); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("Flush after SetReadDeadline = %v, want the write timeout", err)
+	}
+}
+
+func TestConnOtherErrorsSticky(t *testing.T) {
+	a, b := net.Pipe()
+	c := NewConn(a)
+	b.Close()
+	c.WriteString("x")
+	err := c.Flush()
+	if err == nil {
+		t.Fatal("Flush to closed peer succeeded")
+	}
+	c.SetDeadline(time.Time{})
+	if err2 := c.Flush(); err2 != err {
+		t.Fatalf("Flush after SetDeadline = %v, want sticky %v", err2, err)
+	}
+	c.Close()
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+// CopyStats counts the bytes moved by Reader.WriteTo and Writer.ReadFrom,
+// by the path they took. Counting is off unless a CopyStats is attached to
+// a Reader or Writer with SetCopyStats.
+// CopyStats 按照数据经过的路径，统计Reader.WriteTo和Writer.ReadFrom传输的字节数。
+// 只有通过SetCopyStats将CopyStats关联到Reader或Writer上时才会进行统计。
+type CopyStats struct {
+	// Buffered counts bytes copied through the buffer of a Reader or Writer.
+	// Buffered 统计经过Reader或Writer缓冲区拷贝的字节数。
+	Buffered int64
+
+	// WriterTo counts bytes handed to the WriteTo method of the reader
+	// underlying a Reader, and ReaderFrom bytes handed to the ReadFrom
+	// method of the destination or of the writer underlying a Writer.
+	// These are the paths on which *os.File and *net.TCPConn use
+	// zero-copy system calls such as sendfile and splice when the
+	// operating system supports them.
+	// WriterTo 统计交给Reader底层读取器的WriteTo方法的字节数，ReaderFrom统计交给目标写入器
+	// 或者Writer底层写入器的ReadFrom方法的字节数。*os.File和*net.TCPConn在操作系统支持时，
+	// 会在这些路径上使用sendfile和splice之类的零拷贝系统调用。
+	WriterTo   int64
+	ReaderFrom int64
+}
+
+// copyPath is one of the paths counted by CopyStats.
+// copyPath 是CopyStats统计的路径之一。
+type copyPath int
+
+const (
+	copyBuffered copyPath = iota
+	copyWriterTo
+	copyReaderFrom
+)
+
+// count adds n bytes to the counter of path. s may be nil, meaning that
+// counting is off.
+// count 将n个字节加到path的计数上。s可以为nil，表示不进行统计。
+func (s *CopyStats) count(path copyPath, n int64) {
+	if s == nil || n == 0 {
+		return
+	}
+	switch path {
+	case copyBuffered:
+		s.Buffered += n
+	case copyWriterTo:
+		s.WriterTo += n
+	case copyReaderFrom:
+		s.ReaderFrom += n
+	}
+}
+
+// SetCopyStats makes WriteTo add the bytes it moves to s, until b is
+// Reset. A nil s turns counting off, which is the default. Since the
+// counts are not updated atomically, s must not be shared with a Reader
+// or Writer that is used concurrently with b.
+// SetCopyStats 使WriteTo将其传输的字节数累加到s上，直到b被Reset。nil的s会关闭统计，这也是默认状态。
+// 由于计数不是原子更新的，s不能与和b并发使用的Reader或Writer共享。
+func (b *Reader) SetCopyStats(s *CopyStats) { b.stats = s }
+
+// SetCopyStats makes ReadFrom add the bytes it moves to s, until b is
+// Reset. A nil s turns counting off, which is the default. Since the
+// counts are not updated atomically, s must not be shared with a Reader
+// or Writer that is used concurrently with b.
+// SetCopyStats 使ReadFrom将其传输的字节数累加到s上，直到b被Reset。nil的s会关闭统计，这也是默认状态。
+// 由于计数不是原子更新的，s不能与和b并发使用的Reader或Writer共享。
+func (b *Writer) SetCopyStats(s *CopyStats) { b.stats = s }
@@ -0,0 +1,108 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// onlyReader and onlyWriter hide the WriteTo and ReadFrom methods of the
+// values they wrap.
+type onlyReader struct{ io.Reader }
+type onlyWriter struct{ io.Writer }
+
+var copyInput = strings.Repeat("0123456789", 10)
+
+var writeToStatsTests = []struct {
+	name string
+	src  func() io.Reader
+	dst  func(*bytes.Buffer) io.Writer
+	want CopyStats
+}{
+	{
+		"WriterTo source",
+		func() io.Reader { return strings.NewReader(copyInput) },
+		func(b *bytes.Buffer) io.Writer { return b },
+		CopyStats{Buffered: 16, WriterTo: 84},
+	},
+	{
+		"ReaderFrom destination",
+		func() io.Reader { return onlyReader{strings.NewReader(copyInput)} },
+		func(b *bytes.Buffer) io.Writer { return b },
+		CopyStats{Buffered: 16, ReaderFrom: 84},
+	},
+	{
+		"neither",
+		func() io.Reader { return onlyReader{strings.NewReader(copyInput)} },
+		func(b *bytes.Buffer) io.Writer { return onlyWriter{b} },
+		CopyStats{Buffered: 100},
+	},
+}
+
+func TestReaderWriteToStats(t *testing.T) {
+	for _, tt := range writeToStatsTests {
+		r := NewReaderSize(tt.src(), 16)
+		var stats CopyStats
+		r.SetCopyStats(&stats)
+		// Fill the buffer, so that WriteTo has to drain it first.
+		r.Peek(1)
+		var out bytes.Buffer
+		n, err := r.WriteTo(tt.dst(&out))
+		if n != 100 || err != nil || out.String() != copyInput {
+			t.Fatalf("%s: WriteTo = %d, %v", tt.name, n, err)
+		}
+		if stats != tt.want {
+			t.Errorf("%s: stats = %+v, want %+v", tt.name, stats, tt.want)
+		}
+	}
+}
+
+var readFromStatsTests = []struct {
+	name string
+	dst  func(*bytes.Buffer) io.Writer
+	want CopyStats
+}{
+	{"ReaderFrom destination", func(b *bytes.Buffer) io.Writer { return b }, CopyStats{ReaderFrom: 100}},
+	{"plain destination", func(b *bytes.Buffer) io.Writer { return onlyWriter{b} }, CopyStats{Buffered: 100}},
+}
+
+func TestWriterReadFromStats(t *testing.T) {
+	for _, tt := range readFromStatsTests {
+		var out bytes.Buffer
+		w := NewWriterSize(tt.dst(&out), 16)
+		var stats CopyStats
+		w.SetCopyStats(&stats)
+		w.WriteString("hdr")
+		n, err := w.ReadFrom(strings.NewReader(copyInput))
+		if n != 100 || err != nil {
+			t.Fatalf("%s: ReadFrom = %d, %v", tt.name, n, err)
+		}
+		if err := w.Flush(); err != nil || out.String() != "hdr"+copyInput {
+			t.Fatalf("%s: Flush = %v, wrote %q", tt.name, err, out.String())
+		}
+		if stats != tt.want {
+			t.Errorf("%s: stats = %+v, want %+v", tt.name, stats, tt.want)
+		}
+	}
+}
+
+func TestCopyStatsReset(t *testing.T) {
+	var stats CopyStats
+	r := NewReader(strings.NewReader("abc"))
+	r.SetCopyStats(&stats)
+	r.Reset(strings.NewReader("abc"))
+	r.WriteTo(io.Discard)
+	w := NewWriter(io.Discard)
+	w.SetCopyStats(&stats)
+	w.Reset(io.Discard)
+	w.ReadFrom(strings.NewReader("abc"))
+	if stats != (CopyStats{}) {
+		t.Fatalf("stats after Reset = %+v, want none", stats)
+	}
+}
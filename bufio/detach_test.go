@@ -0,0 +1,112 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+var detachedReaderOps = []struct {
+	name string
+	op   func(r *Reader) error
+}{
+	{"Read", func(r *Reader) error { _, err := r.Read(make([]byte, 4)); return err }},
+	{"ReadByte", func(r *Reader) error { _, err := r.ReadByte(); return err }},
+	{"ReadRune", func(r *Reader) error { _, _, err := r.ReadRune(); return err }},
+	{"ReadSlice", func(r *Reader) error { _, err := r.ReadSlice('\n'); return err }},
+	{"ReadLine", func(r *Reader) error { _, _, err := r.ReadLine(); return err }},
+	{"ReadString", func(r *Reader) error { _, err := r.ReadString('\n'); return err }},
+	{"Peek", func(r *Reader) error { _, err := r.Peek(1); return err }},
+	{"Discard", func(r *Reader) error { _, err := r.Discard(1); return err }},
+	{"WriteTo", func(r *Reader) error { _, err := r.WriteTo(io.Discard); return err }},
+}
+
+var readerDetachTests = []struct {
+	input    string
+	consume  int // bytes read before Detach
+	buffered string
+}{
+	{"", 0, ""},
+	{"hello, world", 0, ""},
+	{"hello, world", 1, "ello, world"},
+	{"hello, world", 12, ""},
+	{strings.Repeat("x", 20) + "rest", 1, strings.Repeat("x", 15)},
+}
+
+func TestReaderDetach(t *testing.T) {
+	for _, tt := range readerDetachTests {
+		src := strings.NewReader(tt.input)
+		r := NewReaderSize(src, 16)
+		if tt.consume > 0 {
+			if _, err := io.ReadFull(r, make([]byte, tt.consume)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		rd, buffered := r.Detach()
+		if rd != src || string(buffered) != tt.buffered {
+			t.Errorf("%q/%d: Detach = %v, %q, want the source and %q", tt.input, tt.consume, rd, buffered, tt.buffered)
+		}
+		// The caller takes over where the Reader stopped.
+		rest, _ := io.ReadAll(rd)
+		if got := tt.input[tt.consume:]; string(buffered)+string(rest) != got {
+			t.Errorf("%q/%d: buffered and rest = %q, want %q", tt.input, tt.consume, string(buffered)+string(rest), got)
+		}
+		for _, o := range detachedReaderOps {
+			// Twice, since the error must be sticky.
+			for i := 0; i < 2; i++ {
+				if err := o.op(r); err != ErrDetached {
+					t.Errorf("%q/%d: %s after Detach = %v, want ErrDetached", tt.input, tt.consume, o.name, err)
+				}
+			}
+		}
+		r.Reset(strings.NewReader("again"))
+		if s, err := r.ReadString('\n'); s != "again" || err != io.EOF {
+			t.Errorf("%q/%d: ReadString after Reset = %q, %v", tt.input, tt.consume, s, err)
+		}
+	}
+}
+
+var detachedWriterOps = []struct {
+	name string
+	op   func(w *Writer) error
+}{
+	{"Write", func(w *Writer) error { _, err := w.Write([]byte("x")); return err }},
+	{"Write large", func(w *Writer) error { _, err := w.Write(make([]byte, 100)); return err }},
+	{"WriteByte", func(w *Writer) error { return w.WriteByte('x') }},
+	{"WriteRune", func(w *Writer) error { _, err := w.WriteRune('é'); return err }},
+	{"WriteString", func(w *Writer) error { _, err := w.WriteString("x"); return err }},
+	{"ReadFrom", func(w *Writer) error { _, err := w.ReadFrom(strings.NewReader("x")); return err }},
+	{"Flush", func(w *Writer) error { return w.Flush() }},
+}
+
+func TestWriterDetach(t *testing.T) {
+	for _, unflushed := range []string{"", "abc", strings.Repeat("y", 16)} {
+		var out strings.Builder
+		w := NewWriterSize(&out, 16)
+		w.WriteString(unflushed)
+		wr, got := w.Detach()
+		if wr != &out || string(got) != unflushed || out.Len() != 0 {
+			t.Errorf("%q: Detach = %v, %q with %q written, want the destination and %q", unflushed, wr, got, out.String(), unflushed)
+		}
+		for _, o := range detachedWriterOps {
+			for i := 0; i < 2; i++ {
+				if err := o.op(w); err != ErrDetached {
+					t.Errorf("%q: %s after Detach = %v, want ErrDetached", unflushed, o.name, err)
+				}
+			}
+		}
+		if w.Buffered() != 0 || out.Len() != 0 {
+			t.Errorf("%q: detached Writer buffered %d bytes and wrote %q", unflushed, w.Buffered(), out.String())
+		}
+		w.Reset(&out)
+		w.WriteString("z")
+		if err := w.Flush(); err != nil || out.String() != "z" {
+			t.Errorf("%q: Flush after Reset = %v, wrote %q", unflushed, err, out.String())
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+// Exported for testing only.
+
+const (
+	MinReadBufferSize        = minReadBufferSize
+	MaxConsecutiveEmptyReads = maxConsecutiveEmptyReads
+)
+
+// ReaderState returns the buffer of b, the read and write positions in
+// it, and the last byte and rune size recorded for UnreadByte and
+// UnreadRune.
+func ReaderState(b *Reader) (buf []byte, r, w, lastByte, lastRuneSize int) {
+	return b.buf, b.r, b.w, b.lastByte, b.lastRuneSize
+}
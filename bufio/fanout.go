@@ -0,0 +1,179 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+var ErrCursorClosed = errors.New("bufio: read from closed cursor")
+
+// FanOut implements buffering for an io.Reader object shared by several
+// consumers. Each consumer reads through its own Cursor and sees the whole
+// input stream, at its own pace; a FanOut with two cursors is a tee.
+// FanOut 为被多个消费者共享的io.Reader对象实现缓冲。每个消费者通过自己的Cursor读取，
+// 按自己的节奏看到完整的输入流；有两个Cursor的FanOut就是一个tee。
+//
+// The cursors share one buffer. Input is discarded only after every
+// cursor has read it, so the buffer bounds the lag between the fastest
+// and the slowest cursor: a cursor that runs maxLag bytes ahead of the
+// slowest one blocks until that one catches up or is closed. A cursor
+// that stops reading without being closed therefore eventually stalls
+// all the others.
+// 所有Cursor共享一个缓冲区。输入只有在被所有Cursor读取之后才会被丢弃，
+// 因此缓冲区限制了最快和最慢的Cursor之间的差距：领先最慢Cursor maxLag字节的Cursor会阻塞，
+// 直到最慢的Cursor追上来或者被关闭。所以一个既不读取也不关闭的Cursor最终会让其他所有Cursor停滞。
+type FanOut struct {
+	mu   sync.Mutex
+	cond sync.Cond // signaled when data arrives, is discarded or a cursor closes
+
+	buf     []byte
+	rd      io.Reader // reader provided by the client
+	r, w    int       // buf read and write positions
+	base    int64     // input offset of buf[r]
+	err     error     // sticky; returned to each cursor after its data
+	reading bool      // a cursor is reading from rd without holding mu
+
+	cursors []*Cursor
+}
+
+// A Cursor is one consumer's view of the input of a FanOut.
+// It implements io.ReadCloser.
+// Cursor 是一个消费者对FanOut输入的视图。它实现了io.ReadCloser。
+type Cursor struct {
+	f      *FanOut
+	pos    int64 // input offset of the next byte to read
+	closed bool
+}
+
+// NewFanOut returns a new FanOut reading from rd with n cursors, all
+// positioned at the start of the input, and a buffer of maxLag bytes.
+// NewFanOut 返回一个从rd读取的FanOut，它有n个都位于输入开头的Cursor，缓冲区大小为maxLag字节。
+func NewFanOut(rd io.Reader, n, maxLag int) *FanOut {
+	if maxLag < minReadBufferSize {
+		maxLag = minReadBufferSize
+	}
+	f := &FanOut{
+		buf:     make([]byte, maxLag),
+		rd:      rd,
+		cursors: make([]*Cursor, n),
+	}
+	f.cond.L = &f.mu
+	for i := range f.cursors {
+		f.cursors[i] = &Cursor{f: f}
+	}
+	return f
+}
+
+// Cursor returns the i'th cursor of f.
+// Cursor 返回f的第i个Cursor。
+func (f *FanOut) Cursor(i int) *Cursor { return f.cursors[i] }
+
+// discard drops the input that every open cursor has read.
+// Called with f.mu held.
+// discard 丢弃所有未关闭的Cursor都已经读取过的输入。调用时必须持有f.mu。
+func (f *FanOut) discard() {
+	end := f.base + int64(f.w-f.r)
+	min := end
+	for _, c := range f.cursors {
+		if !c.closed && c.pos < min {
+			min = c.pos
+		}
+	}
+	if min > f.base {
+		f.r += int(min - f.base)
+		f.base = min
+		f.cond.Broadcast()
+	}
+}
+
+// fill reads a new chunk into the free part of the buffer. It releases
+// f.mu while reading, so other cursors can keep consuming buffered data.
+// Called with f.mu held and f.reading set.
+// fill 读取新数据到缓冲区的空闲部分。读取期间会释放f.mu，因此其他Cursor可以继续消费已缓冲的数据。
+// 调用时必须持有f.mu并已设置f.reading。
+func (f *FanOut) fill() {
+	// Slide existing data to beginning.
+	// 将现有数据滑动到开头，只有正在读取的Cursor会移动数据
+	if f.r > 0 {
+		copy(f.buf, f.buf[f.r:f.w])
+		f.w -= f.r
+		f.r = 0
+	}
+	p := f.buf[f.w:]
+
+	f.mu.Unlock()
+	var n int
+	var err error
+	for i := maxConsecutiveEmptyReads; i > 0; i-- {
+		n, err = f.rd.Read(p)
+		if n < 0 {
+			panic(errNegativeRead)
+		}
+		if n > 0 || err != nil {
+			break
+		}
+	}
+	if n == 0 && err == nil {
+		err = io.ErrNoProgress
+	}
+	f.mu.Lock()
+
+	f.w += n
+	f.err = err
+	f.reading = false
+	f.cond.Broadcast()
+}
+
+// Read reads data into p from c's position in the input. It returns the
+// error of the underlying reader, often io.EOF, once c has read all data
+// that preceded it.
+// Read 从c在输入中的位置读取数据到p。当c读完错误之前的所有数据后，返回底层读取器的错误（通常为io.EOF）。
+func (c *Cursor) Read(p []byte) (n int, err error) {
+	f := c.f
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for {
+		if c.closed {
+			return 0, ErrCursorClosed
+		}
+		end := f.base + int64(f.w-f.r)
+		if c.pos < end {
+			n = copy(p, f.buf[f.r+int(c.pos-f.base):f.w])
+			c.pos += int64(n)
+			f.discard()
+			return n, nil
+		}
+		if len(p) == 0 || f.err != nil {
+			return 0, f.err
+		}
+		if f.reading || f.w-f.r >= len(f.buf) {
+			// Another cursor is reading, or the slowest cursor
+			// is maxLag bytes behind: wait.
+			// 其他Cursor正在读取，或者最慢的Cursor落后了maxLag字节：等待
+			f.cond.Wait()
+			continue
+		}
+		f.reading = true
+		f.fill()
+	}
+}
+
+// Close detaches c from the FanOut, so that it no longer holds back
+// the other cursors. Reads from c after Close return ErrCursorClosed.
+// Close 将c从FanOut上分离，使其不再拖慢其他Cursor。Close之后从c读取会返回ErrCursorClosed。
+func (c *Cursor) Close() error {
+	f := c.f
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		f.discard()
+		f.cond.Broadcast()
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+var fanOutTests = []struct {
+	name      string
+	reader    func(io.Reader) io.Reader
+	maxLag    int
+	readSizes []int // one per cursor
+}{
+	{"one cursor", func(r io.Reader) io.Reader { return r }, 16, []int{7}},
+	{"tee", func(r io.Reader) io.Reader { return r }, 16, []int{1, 100}},
+	{"half reader", iotest.HalfReader, 64, []int{1, 8, 15, 1000}},
+	{"one byte reader", iotest.OneByteReader, 16, []int{3, 5, 17}},
+	{"data with EOF", iotest.DataErrReader, 4096, []int{10, 4096}},
+}
+
+func TestFanOut(t *testing.T) {
+	data := []byte(strings.Repeat("0123456789abcdefghijklmnopqrstuvwxyz", 300))
+	for _, tt := range fanOutTests {
+		f := NewFanOut(tt.reader(bytes.NewReader(data)), len(tt.readSizes), tt.maxLag)
+		var wg sync.WaitGroup
+		for i, size := range tt.readSizes {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var out bytes.Buffer
+				buf := make([]byte, size)
+				for {
+					n, err := f.Cursor(i).Read(buf)
+					out.Write(buf[:n])
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						t.Errorf("%s: cursor %d: %v", tt.name, i, err)
+						return
+					}
+				}
+				if !bytes.Equal(out.Bytes(), data) {
+					t.Errorf("%s: cursor %d read %d bytes, want the %d bytes of input", tt.name, i, out.Len(), len(data))
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+func TestFanOutError(t *testing.T) {
+	errFail := errors.New("fail")
+	f := NewFanOut(io.MultiReader(strings.NewReader("data"), iotest.ErrReader(errFail)), 2, 16)
+	for i := 0; i < 2; i++ {
+		c := f.Cursor(i)
+		b, err := io.ReadAll(c)
+		if string(b) != "data" || err != errFail {
+			t.Errorf("cursor %d: ReadAll = %q, %v, want %q, %v", i, b, err, "data", errFail)
+		}
+		// The error is sticky.
+		if n, err := c.Read(make([]byte, 1)); n != 0 || err != errFail {
+			t.Errorf("cursor %d: Read after error = %d, %v", i, n, err)
+		}
+	}
+}
+
+// TestFanOutLag checks that a cursor cannot run more than maxLag bytes
+// ahead of the slowest open cursor.
+func TestFanOutLag(t *testing.T) {
+	f := NewFanOut(strings.NewReader(strings.Repeat("x", 100)), 2, 16)
+	fast, slow := f.Cursor(0), f.Cursor(1)
+	if n, err := io.ReadFull(fast, make([]byte, 16)); n != 16 || err != nil {
+		t.Fatalf("ReadFull = %d, %v", n, err)
+	}
+	done := make(chan int)
+	go func() {
+		n, _ := fast.Read(make([]byte, 16))
+		done <- n
+	}()
+	select {
+	case <-done:
+		t.Fatal("Read more than maxLag bytes ahead of the slowest cursor")
+	case <-time.After(10 * time.Millisecond):
+	}
+	if n, err := slow.Read(make([]byte, 4)); n != 4 || err != nil {
+		t.Fatalf("slow Read = %d, %v", n, err)
+	}
+	if n := <-done; n < 1 || n > 4 {
+		t.Fatalf("fast Read = %d bytes, want 1 to 4", n)
+	}
+
+	// Closing the slow cursor releases the fast one.
+	go func() {
+		n, _ := io.ReadFull(fast, make([]byte, 50))
+		done <- n
+	}()
+	slow.Close()
+	if n := <-done; n != 50 {
+		t.Fatalf("ReadFull after Close of slow cursor = %d, want 50", n)
+	}
+	if _, err := slow.Read(make([]byte, 1)); err != ErrCursorClosed {
+		t.Fatalf("Read of closed cursor = %v, want ErrCursorClosed", err)
+	}
+}
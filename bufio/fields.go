@@ -0,0 +1,255 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+var (
+	ErrBareQuote  = errors.New("bufio: bare \" in non-quoted field")
+	ErrFieldQuote = errors.New("bufio: extraneous or missing \" in quoted field")
+)
+
+// FieldReader splits the lines read from a Reader into fields, as in the
+// CSV and TSV formats. It is built on ReadSlice: a record that fits in the
+// Reader's buffer is split where it lies, and only records spanning
+// several refills of the buffer are assembled in a buffer of the
+// FieldReader.
+// FieldReader 将从Reader读取的行拆分成字段，对应CSV和TSV格式。它基于ReadSlice实现：
+// 能放进Reader缓冲区的记录就地拆分，只有跨越多次缓冲区填充的记录才会在FieldReader自己的缓冲区中拼装。
+//
+// Each line is a record, terminated by \n or \r\n, and empty lines are
+// skipped. If Quote is set, a field beginning with a double quote extends
+// to the matching closing quote and may contain the separator, newlines
+// and doubled quotes standing for a single one, as in RFC 4180. Unlike
+// encoding/csv, a FieldReader leaves \r\n inside quoted fields as is and
+// does not trim spaces or check the number of fields per record.
+// 每一行是一条记录，以\n或\r\n结尾，空行会被跳过。如果设置了Quote，以双引号开头的字段延续到与之匹配的结束引号，
+// 其中可以包含分隔符、换行以及表示单个引号的两个连续引号，与RFC 4180一致。
+// 与encoding/csv不同，FieldReader会原样保留引号字段中的\r\n，并且不修剪空格，也不检查每条记录的字段数。
+type FieldReader struct {
+	// Comma is the field separator.
+	// Comma 是字段分隔符。
+	Comma byte
+
+	// Quote enables double-quoted fields. Without it, double quotes
+	// are ordinary characters.
+	// Quote 启用双引号字段。未启用时，双引号是普通字符。
+	Quote bool
+
+	// ReuseRecord selects the zero-allocation mode: ReadRecord returns
+	// fields that point into the Reader's buffer, or into a buffer of the
+	// FieldReader for records that do not fit, and reuses the slice of
+	// fields. They are valid only until the next read.
+	// ReuseRecord 选择零分配模式：ReadRecord返回的字段指向Reader的缓冲区，
+	// 无法放入的记录则指向FieldReader的缓冲区，并且会复用字段切片。它们只在下一次读取之前有效。
+	ReuseRecord bool
+
+	rd     *Reader
+	rec    []byte   // assembly buffer for records spanning refills
+	fields [][]byte // reused by ReuseRecord
+	line   int      // line number of the current record
+	next   int      // line number of the next line
+}
+
+// NewCSVReader returns a FieldReader for comma-separated values with
+// double-quoted fields.
+// NewCSVReader 返回一个用于逗号分隔值、支持双引号字段的FieldReader。
+func NewCSVReader(rd *Reader) *FieldReader {
+	return &FieldReader{rd: rd, Comma: ',', Quote: true}
+}
+
+// NewTSVReader returns a FieldReader for tab-separated values, in which
+// quotes have no special meaning.
+// NewTSVReader 返回一个用于制表符分隔值的FieldReader，其中引号没有特殊含义。
+func NewTSVReader(rd *Reader) *FieldReader {
+	return &FieldReader{rd: rd, Comma: '\t'}
+}
+
+// Line returns the line number at which the last record read by ReadRecord
+// started, counting from 1.
+// Line 返回ReadRecord最后读取的记录的起始行号，从1开始计数。
+func (f *FieldReader) Line() int { return f.line }
+
+// ReadRecord reads the next record and returns its fields.
+// ReadRecord 读取下一条记录并返回其字段。
+//
+// Unless ReuseRecord is set, the fields share one newly allocated copy of
+// the record and stay valid. At the end of the input ReadRecord returns
+// io.EOF; a final record without line terminator is returned without
+// error. A quoting error, ErrBareQuote or ErrFieldQuote, applies only to
+// the record just read, and the next call continues with the following
+// record. A quoted field still open at the end of the input yields
+// ErrFieldQuote.
+// 除非设置了ReuseRecord，字段共享该记录的一份新分配的拷贝，并一直有效。输入结束时ReadRecord返回io.EOF；
+// 缺少行结束符的最后一条记录会被正常返回。引号错误（ErrBareQuote或ErrFieldQuote）只针对刚读取的记录，
+// 下一次调用会继续读取之后的记录。到输入结束时仍未闭合的引号字段会导致ErrFieldQuote。
+func (f *FieldReader) ReadRecord() ([][]byte, error) {
+	for {
+		f.line = f.next + 1
+		rec, err := f.readRecord()
+		if err != nil {
+			return nil, err
+		}
+		if len(rec) == 0 {
+			continue
+		}
+		if !f.ReuseRecord {
+			rec = bytes.Clone(rec)
+		}
+		return f.split(rec)
+	}
+}
+
+// fieldState is the state of the scan for the end of a record.
+// fieldState 是查找记录结尾时的扫描状态。
+type fieldState int
+
+const (
+	fieldStart  fieldState = iota // at the start of a field
+	fieldPlain                    // in a field without quotes
+	fieldQuoted                   // in a quoted field
+	fieldQuote                    // after a quote in a quoted field
+)
+
+// scan advances st over p and reports the state at the end of p.
+// scan 让st扫描过p，并报告p结尾处的状态。
+func (f *FieldReader) scan(st fieldState, p []byte) fieldState {
+	if !f.Quote {
+		return fieldPlain
+	}
+	for _, c := range p {
+		switch st {
+		case fieldStart:
+			switch c {
+			case '"':
+				st = fieldQuoted
+			case f.Comma, '\n':
+			default:
+				st = fieldPlain
+			}
+		case fieldPlain:
+			if c == f.Comma || c == '\n' {
+				st = fieldStart
+			}
+		case fieldQuoted:
+			if c == '"' {
+				st = fieldQuote
+			}
+		case fieldQuote:
+			switch c {
+			case '"':
+				st = fieldQuoted
+			case f.Comma, '\n':
+				st = fieldStart
+			default:
+				st = fieldPlain
+			}
+		}
+	}
+	return st
+}
+
+// readRecord returns the bytes of the next record without its line
+// terminator. The result points into the Reader's buffer if the record
+// fits in it, and into f.rec otherwise.
+// readRecord 返回下一条记录去掉行结束符后的字节。如果记录能放进Reader的缓冲区，结果指向该缓冲区，否则指向f.rec。
+func (f *FieldReader) readRecord() ([]byte, error) {
+	line, err := f.rd.ReadSlice('\n')
+	st := f.scan(fieldStart, line)
+	if err == nil {
+		f.next++
+	}
+	if err == ErrBufferFull || err == nil && st == fieldQuoted {
+		// The record continues beyond the buffered data:
+		// assemble it in f.rec.
+		// 记录延续到了已缓冲的数据之外：在f.rec中拼装
+		rec := append(f.rec[:0], line...)
+		for err == ErrBufferFull || err == nil && st == fieldQuoted {
+			line, err = f.rd.ReadSlice('\n')
+			st = f.scan(st, line)
+			if err == nil {
+				f.next++
+			}
+			rec = append(rec, line...)
+		}
+		f.rec = rec
+		line = rec
+	}
+	if err == nil {
+		line = line[:len(line)-1]
+		if n := len(line); n > 0 && line[n-1] == '\r' {
+			line = line[:n-1]
+		}
+		return line, nil
+	}
+	if err != io.EOF || len(line) == 0 {
+		return nil, err
+	}
+	// io.EOF with data: final record without line terminator.
+	// 遇到io.EOF但有数据：最后一条记录缺少行结束符
+	f.next++
+	if st == fieldQuoted {
+		return nil, ErrFieldQuote
+	}
+	return line, nil
+}
+
+// split splits rec into fields, removing the quotes in place.
+// split 将rec拆分成字段，并就地去除引号。
+func (f *FieldReader) split(rec []byte) ([][]byte, error) {
+	var fields [][]byte
+	if f.ReuseRecord {
+		fields = f.fields[:0]
+	}
+	for i := 0; ; {
+		if f.Quote && i < len(rec) && rec[i] == '"' {
+			start, w := i, i
+			for i++; ; i++ {
+				if i == len(rec) {
+					return nil, ErrFieldQuote
+				}
+				if rec[i] == '"' {
+					if i+1 == len(rec) || rec[i+1] != '"' {
+						break
+					}
+					i++ // doubled quote
+				}
+				rec[w] = rec[i]
+				w++
+			}
+			i++ // closing quote
+			fields = append(fields, rec[start:w:w])
+			if i == len(rec) {
+				break
+			}
+			if rec[i] != f.Comma {
+				return nil, ErrFieldQuote
+			}
+			i++
+			continue
+		}
+		end := len(rec)
+		if j := bytes.IndexByte(rec[i:], f.Comma); j >= 0 {
+			end = i + j
+		}
+		field := rec[i:end:end]
+		if f.Quote && bytes.IndexByte(field, '"') >= 0 {
+			return nil, ErrBareQuote
+		}
+		fields = append(fields, field)
+		if end == len(rec) {
+			break
+		}
+		i = end + 1
+	}
+	if f.ReuseRecord {
+		f.fields = fields
+	}
+	return fields, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"bufio/bufiotest"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fieldRecord is the result of one ReadRecord call.
+type fieldRecord struct {
+	fields []string
+	line   int
+	err    error
+}
+
+var longField = strings.Repeat("x", 40)
+
+var fieldTests = []struct {
+	name  string
+	tsv   bool
+	input string
+	want  []fieldRecord
+}{
+	{
+		name:  "simple",
+		input: "a,b,c\n1,2,3\n",
+		want:  []fieldRecord{{[]string{"a", "b", "c"}, 1, nil}, {[]string{"1", "2", "3"}, 2, nil}},
+	},
+	{
+		name:  "CRLF and no final newline",
+		input: "a,b\r\n\r\n\nc,d",
+		want:  []fieldRecord{{[]string{"a", "b"}, 1, nil}, {[]string{"c", "d"}, 4, nil}},
+	},
+	{
+		name:  "empty fields",
+		input: ",\n,,x\n",
+		want:  []fieldRecord{{[]string{"", ""}, 1, nil}, {[]string{"", "", "x"}, 2, nil}},
+	},
+	{
+		name:  "quoted",
+		input: `"a,b","say ""hi""",""` + "\n",
+		want:  []fieldRecord{{[]string{"a,b", `say "hi"`, ""}, 1, nil}},
+	},
+	{
+		name:  "quoted newlines",
+		input: "ok,\"1\n2\r\n3\"\nnext\n",
+		want:  []fieldRecord{{[]string{"ok", "1\n2\r\n3"}, 1, nil}, {[]string{"next"}, 4, nil}},
+	},
+	{
+		name:  "bare quote",
+		input: "a,b\"c\nnext\n",
+		want:  []fieldRecord{{nil, 1, ErrBareQuote}, {[]string{"next"}, 2, nil}},
+	},
+	{
+		name:  "text after closing quote",
+		input: "x\n\"y\"z,1\nnext\n",
+		want:  []fieldRecord{{[]string{"x"}, 1, nil}, {nil, 2, ErrFieldQuote}, {[]string{"next"}, 3, nil}},
+	},
+	{
+		name:  "quote open at EOF",
+		input: "a\n\"open\nstill open",
+		want:  []fieldRecord{{[]string{"a"}, 1, nil}, {nil, 2, ErrFieldQuote}},
+	},
+	{
+		name:  "records spanning refills",
+		input: longField + "," + longField + "\n\"" + longField + "\n" + longField + "\",y\nz\n",
+		want: []fieldRecord{
+			{[]string{longField, longField}, 1, nil},
+			{[]string{longField + "\n" + longField, "y"}, 2, nil},
+			{[]string{"z"}, 4, nil},
+		},
+	},
+	{
+		name:  "quote spanning refills",
+		input: "\"" + longField + `""` + longField + "\"\n",
+		want:  []fieldRecord{{[]string{longField + `"` + longField}, 1, nil}},
+	},
+	{
+		name:  "TSV ignores quotes",
+		tsv:   true,
+		input: "a\t\"b\t\n\nc",
+		want:  []fieldRecord{{[]string{"a", `"b`, ""}, 1, nil}, {[]string{"c"}, 3, nil}},
+	},
+}
+
+func TestFieldReader(t *testing.T) {
+	for _, tt := range fieldTests {
+		for _, chunk := range []int{0, 1, 7} {
+			for _, reuse := range []bool{false, true} {
+				src := &bufiotest.Reader{R: strings.NewReader(tt.input)}
+				if chunk > 0 {
+					src.Chunks = []int{chunk}
+				}
+				rd := NewReaderSize(src, 16)
+				f := NewCSVReader(rd)
+				if tt.tsv {
+					f = NewTSVReader(rd)
+				}
+				f.ReuseRecord = reuse
+				var got []fieldRecord
+				for {
+					rec, err := f.ReadRecord()
+					if err == io.EOF {
+						break
+					}
+					var fields []string
+					for _, field := range rec {
+						fields = append(fields, string(field))
+					}
+					got = append(got, fieldRecord{fields, f.Line(), err})
+					if len(got) > len(tt.want) {
+						break
+					}
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%s/%d/%v:\ngot  %v\nwant %v", tt.name, chunk, reuse, got, tt.want)
+				}
+			}
+		}
+	}
+}
+
+// TestFieldReaderKeep checks that without ReuseRecord the fields of a
+// record stay valid after later reads.
+func TestFieldReaderKeep(t *testing.T) {
+	f := NewCSVReader(NewReaderSize(strings.NewReader("a,b\n"+longField+"\nc,d\n"), 16))
+	var recs [][][]byte
+	for {
+		rec, err := f.ReadRecord()
+		if err != nil {
+			break
+		}
+		recs = append(recs, rec)
+	}
+	if len(recs) != 3 || string(recs[0][1]) != "b" || string(recs[1][0]) != longField || string(recs[2][0]) != "c" {
+		t.Fatalf("records = %q", recs)
+	}
+}
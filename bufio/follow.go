@@ -0,0 +1,268 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	ErrFollowTruncated = errors.New("bufio: followed file was truncated")
+	ErrFollowRotated   = errors.New("bufio: followed file was rotated")
+)
+
+const (
+	defaultFollowInterval = time.Second
+	followTailLen         = 64 // bytes kept to detect rewrites
+)
+
+// Follower is an io.Reader for a file that is still being written to, in
+// the manner of "tail -f". Instead of reporting io.EOF at the end of the
+// file, Read blocks until more data is appended, the file is truncated or
+// rotated, or the Follower is closed. Wrapping a Follower in a Reader
+// therefore gives a Reader that never sees the end of a growing file.
+// Follower 是一个用于仍在被写入的文件的io.Reader，行为类似"tail -f"。
+// 在文件末尾Read不会返回io.EOF，而是阻塞直到有新数据追加、文件被截断或轮转，或者Follower被关闭。
+// 因此用Reader包装Follower，得到的Reader永远不会看到增长中文件的末尾。
+//
+// Truncation and rotation are reported as the errors ErrFollowTruncated and
+// ErrFollowRotated, once, after all data of the old file contents has been
+// returned; reading continues from the start of the new contents. Since a
+// Reader does not keep errors returned by its underlying reader once they
+// have been reported, a Reader wrapping a Follower keeps working after
+// such an event.
+// 截断和轮转通过ErrFollowTruncated和ErrFollowRotated错误报告，且只报告一次，
+// 报告时旧文件内容的所有数据都已经返回；之后从新内容的开头继续读取。
+// 由于Reader在报告底层读取器的错误后不会保留这些错误，包装Follower的Reader在此类事件后仍然可以继续工作。
+//
+// Rotation is detected by comparing the open file with the one found at
+// its name (see os.SameFile). Truncation is detected when the file is
+// shorter than what has been read, or when the last bytes read have
+// changed, which catches a file truncated and rewritten past the read
+// offset while Read waits. A file rewritten while Read is still returning
+// data, or rewritten with the same last bytes, cannot be told apart from
+// one that was only appended to.
+// 通过比较打开的文件和位于其名称处的文件来检测轮转（参见os.SameFile）。当文件比已读取的部分更短，
+// 或者最后读取的那些字节发生了变化时，就认为文件被截断了，后者可以发现在Read等待期间被截断并重写到读取偏移量之后的文件。
+// 如果文件在Read仍在返回数据时被重写，或者重写后最后的那些字节不变，则无法与仅仅被追加的文件区分开来。
+type Follower struct {
+	// PollInterval is how long Read waits at the end of the file before
+	// checking for new data. If zero, a default of one second is used.
+	// PollInterval 是Read在文件末尾等待多长时间后再检查新数据。如果为0，则使用默认的1秒。
+	PollInterval time.Duration
+
+	// Notify optionally delivers notifications that the file may have
+	// changed, for example from a file system watcher. A waiting Read
+	// checks for new data as soon as it receives from Notify, without
+	// waiting for the poll interval to elapse.
+	// Notify 可选地传递文件可能已经改变的通知，例如来自文件系统监视器的通知。
+	// 等待中的Read从Notify收到通知后立即检查新数据，而不必等待轮询间隔结束。
+	Notify <-chan struct{}
+
+	name string
+	off  int64 // offset of the next byte to read from file
+
+	// The last tailLen bytes before off, and the modification time and
+	// size of the file when they were last compared with it.
+	// off之前的最后tailLen个字节，以及上一次将它们与文件比较时文件的修改时间和大小
+	tail    [followTailLen]byte
+	tailLen int
+	mod     time.Time
+	size    int64
+
+	mu   sync.Mutex // guards file
+	file *os.File
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewFollower returns a Follower reading f from its current offset.
+// Rotation is detected by comparing f with the file currently found at
+// f.Name(). The Follower takes ownership of f and closes it on Close or
+// when it switches to a rotated file.
+// NewFollower 返回一个从f当前偏移量开始读取的Follower。
+// 通过比较f和当前位于f.Name()的文件来检测轮转。Follower接管f，并在Close或切换到轮转后的文件时关闭它。
+func NewFollower(f *os.File) *Follower {
+	off, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		off = 0
+	}
+	return &Follower{
+		name: f.Name(),
+		off:  off,
+		file: f,
+		done: make(chan struct{}),
+	}
+}
+
+// Read reads data into p. At the end of the file it blocks until data is
+// available; it returns io.EOF only after Close has been called.
+// Read 将数据读入p。在文件末尾它会阻塞直到有数据可读；只有在调用Close之后才返回io.EOF。
+func (f *Follower) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for {
+		if f.closed() {
+			return 0, io.EOF
+		}
+		f.mu.Lock()
+		file := f.file
+		f.mu.Unlock()
+		n, err = file.Read(p)
+		f.off += int64(n)
+		if n > 0 {
+			f.remember(p[:n])
+			return n, nil
+		}
+		if err != io.EOF {
+			if f.closed() {
+				return 0, io.EOF
+			}
+			return 0, err
+		}
+		// At the end of the file: no data yet. Once woken, check the
+		// file before reading on, since new contents may already extend
+		// past f.off.
+		// 到达文件末尾：说明暂时还没有数据。被唤醒后，在继续读取之前先检查文件，因为新的内容可能已经超过了f.off
+		f.wait()
+		if err := f.check(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// remember records p, just read, as the bytes before f.off.
+// remember 将刚刚读取的p记录为f.off之前的字节。
+func (f *Follower) remember(p []byte) {
+	if len(p) >= len(f.tail) {
+		copy(f.tail[:], p[len(p)-len(f.tail):])
+		f.tailLen = len(f.tail)
+		return
+	}
+	copy(f.tail[:], f.tail[len(p):])
+	copy(f.tail[len(f.tail)-len(p):], p)
+	f.tailLen = min(f.tailLen+len(p), len(f.tail))
+}
+
+// rewritten reports whether the bytes before f.off no longer match those
+// that were read. The file is only read again if its modification time or
+// size changed since the last comparison.
+// Called with f.mu held.
+// rewritten 报告f.off之前的字节是否与已读取的字节不再相同。只有当文件的修改时间或大小自上一次比较以来发生变化时，
+// 才会再次读取文件。调用时必须持有f.mu。
+func (f *Follower) rewritten(fi os.FileInfo) bool {
+	if fi.ModTime().Equal(f.mod) && fi.Size() == f.size {
+		return false
+	}
+	f.mod, f.size = fi.ModTime(), fi.Size()
+	if f.tailLen == 0 {
+		return false
+	}
+	var buf [followTailLen]byte
+	n := f.tailLen
+	if _, err := f.file.ReadAt(buf[:n], f.off-int64(n)); err != nil {
+		return false
+	}
+	return !bytes.Equal(buf[:n], f.tail[len(f.tail)-n:])
+}
+
+// reset starts reading the contents of f.file from the beginning.
+// Called with f.mu held.
+// reset 从头开始读取f.file的内容。调用时必须持有f.mu。
+func (f *Follower) reset() {
+	f.off = 0
+	f.tailLen = 0
+	f.mod, f.size = time.Time{}, 0
+}
+
+// check looks for truncation or rotation of the followed file and, if it
+// finds one, switches to the new contents and reports the event.
+// check 检查被跟踪的文件是否被截断或轮转，如果是，则切换到新的内容并报告该事件。
+func (f *Follower) check() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed() {
+		return nil
+	}
+	fi, err := f.file.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() < f.off || f.rewritten(fi) {
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		f.reset()
+		return ErrFollowTruncated
+	}
+	nfi, err := os.Stat(f.name)
+	if err != nil || os.SameFile(fi, nfi) {
+		// Either the same file, or the old one was moved away and
+		// the new one has not been created yet.
+		// 要么是同一个文件，要么旧文件已被移走而新文件还没有创建
+		return nil
+	}
+	if fi.Size() > f.off {
+		// Return the rest of the old file first; Read checks again
+		// once it reaches its end.
+		// 先返回旧文件剩余的内容；Read到达其末尾时会再次检查
+		return nil
+	}
+	nf, err := os.Open(f.name)
+	if err != nil {
+		return nil
+	}
+	f.file.Close()
+	f.file = nf
+	f.reset()
+	return ErrFollowRotated
+}
+
+// wait blocks until a notification arrives, the poll interval elapses
+// or f is closed.
+// wait 阻塞直到收到通知、轮询间隔结束或者f被关闭。
+func (f *Follower) wait() {
+	d := f.PollInterval
+	if d <= 0 {
+		d = defaultFollowInterval
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-f.Notify:
+	case <-f.done:
+	}
+}
+
+func (f *Follower) closed() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops following and closes the file. A Read blocked at the end of
+// the file, and every later Read, returns io.EOF.
+// Close 停止跟踪并关闭文件。阻塞在文件末尾的Read以及之后的所有Read都将返回io.EOF。
+func (f *Follower) Close() error {
+	var err error
+	f.closeOnce.Do(func() {
+		close(f.done)
+		f.mu.Lock()
+		err = f.file.Close()
+		f.mu.Unlock()
+	})
+	return err
+}
@@ -0,0 +1,231 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func appendFile(s string) func(name string) error {
+	return func(name string) error {
+		f, err := os.OpenFile(name, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		if _, err := f.WriteString(s); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+}
+
+func rewriteFile(s string) func(name string) error {
+	return func(name string) error {
+		return os.WriteFile(name, []byte(s), 0644)
+	}
+}
+
+func rotateFile(s string) func(name string) error {
+	return func(name string) error {
+		if err := os.Rename(name, name+".1"); err != nil {
+			return err
+		}
+		return os.WriteFile(name, []byte(s), 0644)
+	}
+}
+
+func replaceFile(s string) func(name string) error {
+	return func(name string) error {
+		if err := os.WriteFile(name+".tmp", []byte(s), 0644); err != nil {
+			return err
+		}
+		return os.Rename(name+".tmp", name)
+	}
+}
+
+// Each change is made to a file holding "first line\n" while a Follower
+// that has read that line waits for more.
+var followTests = []struct {
+	name   string
+	change func(name string) error
+	err    error  // reported by the Follower
+	want   string // next line read
+}{
+	{"append", appendFile("second line\n"), nil, "second line\n"},
+	{"truncate", rewriteFile("x\n"), ErrFollowTruncated, "x\n"},
+	{"truncate to empty", rewriteFile(""), ErrFollowTruncated, ""},
+	{"truncate and rewrite past offset", rewriteFile("a longer line than before\n"), ErrFollowTruncated, "a longer line than before\n"},
+	{"rotate", rotateFile("new file\n"), ErrFollowRotated, "new file\n"},
+	{"replace", replaceFile("new file\n"), ErrFollowRotated, "new file\n"},
+}
+
+func TestFollower(t *testing.T) {
+	for _, tt := range followTests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "log")
+			if err := os.WriteFile(name, []byte("first line\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			f, err := os.Open(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			notify := make(chan struct{})
+			fo := NewFollower(f)
+			fo.PollInterval = time.Hour
+			fo.Notify = notify
+			defer fo.Close()
+
+			r := NewReader(fo)
+			if s, err := r.ReadString('\n'); s != "first line\n" || err != nil {
+				t.Fatalf("ReadString = %q, %v", s, err)
+			}
+			type result struct {
+				s   string
+				err error
+			}
+			done := make(chan result)
+			go func() {
+				s, err := r.ReadString('\n')
+				done <- result{s, err}
+			}()
+			waitForFollower()
+			if err := tt.change(name); err != nil {
+				t.Fatal(err)
+			}
+			notify <- struct{}{}
+			res := <-done
+			if tt.err == nil {
+				if res.s != tt.want || res.err != nil {
+					t.Fatalf("ReadString = %q, %v, want %q, nil", res.s, res.err, tt.want)
+				}
+				return
+			}
+			if res.s != "" || res.err != tt.err {
+				t.Fatalf("ReadString = %q, %v, want \"\", %v", res.s, res.err, tt.err)
+			}
+			if tt.want == "" {
+				return
+			}
+			if s, err := r.ReadString('\n'); s != tt.want || err != nil {
+				t.Fatalf("ReadString after %v = %q, %v, want %q, nil", tt.err, s, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestFollowerRotateUnread rotates a file after appending to it while the
+// Follower waits: the appended data must be read before the rotation is
+// reported.
+func TestFollowerRotateUnread(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "log")
+	if err := os.WriteFile(name, []byte("first line\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notify := make(chan struct{}, 1)
+	fo := NewFollower(f)
+	fo.PollInterval = time.Hour
+	fo.Notify = notify
+	defer fo.Close()
+
+	r := NewReader(fo)
+	if s, err := r.ReadString('\n'); s != "first line\n" || err != nil {
+		t.Fatalf("ReadString = %q, %v", s, err)
+	}
+	done := make(chan error)
+	go func() {
+		_, err := r.Peek(1)
+		done <- err
+	}()
+	waitForFollower()
+	if err := appendFile("last line\n")(name); err != nil {
+		t.Fatal(err)
+	}
+	if err := rotateFile("new file\n")(name); err != nil {
+		t.Fatal(err)
+	}
+	notify <- struct{}{}
+	if err := <-done; err != nil {
+		t.Fatalf("Peek = %v", err)
+	}
+	if s, err := r.ReadString('\n'); s != "last line\n" || err != nil {
+		t.Fatalf("ReadString = %q, %v, want %q, nil", s, err, "last line\n")
+	}
+	notify <- struct{}{}
+	if s, err := r.ReadString('\n'); s != "" || err != ErrFollowRotated {
+		t.Fatalf("ReadString at the end of the old file = %q, %v, want \"\", %v", s, err, ErrFollowRotated)
+	}
+	if s, err := r.ReadString('\n'); s != "new file\n" || err != nil {
+		t.Fatalf("ReadString after rotation = %q, %v, want %q, nil", s, err, "new file\n")
+	}
+}
+
+func TestFollowerStartOffset(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "log")
+	if err := os.WriteFile(name, []byte("old\nnew\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Seek(4, io.SeekStart)
+	fo := NewFollower(f)
+	defer fo.Close()
+	if s, err := NewReader(fo).ReadString('\n'); s != "new\n" || err != nil {
+		t.Fatalf("ReadString = %q, %v, want %q, nil", s, err, "new\n")
+	}
+}
+
+func TestFollowerClose(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "log")
+	if err := os.WriteFile(name, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fo := NewFollower(f)
+	fo.PollInterval = time.Hour
+	done := make(chan error)
+	go func() {
+		_, err := fo.Read(make([]byte, 10))
+		done <- err
+	}()
+	waitForFollower()
+	fo.Close()
+	if err := <-done; err != io.EOF {
+		t.Fatalf("Read blocked during Close = %v, want io.EOF", err)
+	}
+	if _, err := fo.Read(make([]byte, 10)); err != io.EOF {
+		t.Fatalf("Read after Close = %v, want io.EOF", err)
+	}
+}
+
+// waitForFollower waits until a Follower is blocked at the end of its
+// file.
+func waitForFollower() {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if strings.Contains(string(buf[:n]), ".(*Follower).wait(") {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+var (
+	ErrFrameTooLarge = errors.New("bufio: frame too large")
+	ErrFrameDelim    = errors.New("bufio: frame contains delimiter")
+)
+
+// frameKind selects how records are delimited in the stream.
+// frameKind 决定了数据流中记录（帧）的分隔方式。
+type frameKind int
+
+const (
+	frameVarint frameKind = iota // uvarint length, then payload
+	frameFixed                   // big-endian length of hdr bytes, then payload
+	frameDelim                   // payload, then delim
+)
+
+// FrameReader reads length-prefixed or delimiter-terminated records
+// (frames) from a Reader.
+// FrameReader 从Reader中读取长度前缀或者以分隔符结尾的记录（帧）。
+//
+// Whenever a frame fits in the Reader's buffer, ReadFrame returns a slice
+// pointing at the bytes in that buffer, so no copy is made. Larger frames,
+// up to the maximum frame size, are assembled in a buffer owned by the
+// FrameReader. In both cases the bytes stop being valid at the next read.
+// 只要帧能放进Reader的缓冲区，ReadFrame就返回指向该缓冲区的切片，不做拷贝。
+// 更大的帧（不超过最大帧大小）在FrameReader自己持有的缓冲区中拼装。
+// 两种情况下，返回的字节在下一次读取时都将失效。
+type FrameReader struct {
+	rd    *Reader
+	kind  frameKind
+	hdr   int  // header length for frameFixed
+	delim byte // delimiter for frameDelim
+	max   int
+	buf   []byte // assembly buffer for frames larger than rd's buffer
+	err   error  // sticky framing error
+}
+
+// NewVarintFrameReader returns a FrameReader reading frames that are
+// prefixed with their length encoded as an unsigned varint
+// (see encoding/binary). If max <= 0, the size of rd's buffer is used
+// as the maximum frame size.
+// NewVarintFrameReader 返回一个读取以uvarint编码长度为前缀的帧的FrameReader。
+// 如果max <= 0，则使用rd缓冲区的大小作为最大帧大小。
+func NewVarintFrameReader(rd *Reader, max int) *FrameReader {
+	return newFrameReader(rd, frameVarint, 0, 0, max)
+}
+
+// NewFixedFrameReader returns a FrameReader reading frames that are
+// prefixed with a big-endian length of headerLen bytes, which must be
+// between 1 and 8. If max <= 0, the size of rd's buffer is used as the
+// maximum frame size.
+// NewFixedFrameReader 返回一个读取以headerLen字节大端序长度为前缀的帧的FrameReader，
+// headerLen的取值范围为1到8。
+func NewFixedFrameReader(rd *Reader, headerLen, max int) *FrameReader {
+	if headerLen < 1 || headerLen > 8 {
+		panic("bufio: invalid frame header length")
+	}
+	return newFrameReader(rd, frameFixed, headerLen, 0, max)
+}
+
+// NewDelimFrameReader returns a FrameReader reading frames terminated by
+// delim, such as newline-delimited JSON. The delimiter is not part of the
+// returned frame. If max <= 0, the size of rd's buffer is used as the
+// maximum frame size.
+// NewDelimFrameReader 返回一个读取以delim结尾的帧（例如以换行分隔的JSON）的FrameReader。
+// 返回的帧不包含分隔符。
+func NewDelimFrameReader(rd *Reader, delim byte, max int) *FrameReader {
+	return newFrameReader(rd, frameDelim, 0, delim, max)
+}
+
+func newFrameReader(rd *Reader, kind frameKind, hdr int, delim byte, max int) *FrameReader {
+	if max <= 0 {
+		max = rd.Size()
+	}
+	return &FrameReader{
+		rd:    rd,
+		kind:  kind,
+		hdr:   hdr,
+		delim: delim,
+		max:   max,
+	}
+}
+
+// ReadFrame reads the next frame and returns its payload.
+// ReadFrame 读取下一个帧并返回其负载。
+//
+// At the end of the input ReadFrame returns io.EOF; a frame cut short by
+// the end of the input yields io.ErrUnexpectedEOF. As with ReadLine, a
+// delimited frame missing only its final delimiter is returned without
+// error. If a frame is larger than the maximum frame size, ReadFrame
+// returns ErrFrameTooLarge; since the stream can no longer be framed,
+// all subsequent calls return the same error.
+// 输入结束时ReadFrame返回io.EOF；被输入结束截断的帧返回io.ErrUnexpectedEOF。
+// 和ReadLine一样，仅缺少最后一个分隔符的帧会被正常返回。
+// 如果帧大于最大帧大小，ReadFrame返回ErrFrameTooLarge；此后数据流无法再被正确分帧，
+// 因此后续调用都将返回同样的错误。
+func (f *FrameReader) ReadFrame() ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.kind == frameDelim {
+		return f.readDelim()
+	}
+
+	n, err := f.readHeader()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(f.max) {
+		f.err = ErrFrameTooLarge
+		return nil, f.err
+	}
+	return f.readPayload(int(n))
+}
+
+// readHeader reads and decodes the length prefix of the next frame.
+// readHeader 读取并解码下一个帧的长度前缀。
+func (f *FrameReader) readHeader() (uint64, error) {
+	if f.kind == frameVarint {
+		// binary.ReadUvarint reports io.ErrUnexpectedEOF for a truncated varint.
+		return binary.ReadUvarint(f.rd)
+	}
+	hdr, err := f.rd.Peek(f.hdr)
+	if err != nil {
+		if len(hdr) > 0 && err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, err
+	}
+	var n uint64
+	for _, c := range hdr {
+		n = n<<8 | uint64(c)
+	}
+	f.rd.Discard(f.hdr)
+	return n, nil
+}
+
+// readPayload returns the next n bytes, as a view into the Reader's buffer
+// if they fit and as a copy into f.buf otherwise.
+// readPayload 返回接下来的n个字节：能放进Reader缓冲区时返回缓冲区的视图，否则拷贝到f.buf中。
+func (f *FrameReader) readPayload(n int) ([]byte, error) {
+	if n <= f.rd.Size() {
+		p, err := f.rd.Peek(n)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		f.rd.Discard(n)
+		return p, nil
+	}
+	if cap(f.buf) < n {
+		f.buf = make([]byte, n)
+	}
+	p := f.buf[:n]
+	if _, err := io.ReadFull(f.rd, p); err != nil {
+		if err == io.EOF {
+			// The header was read, so the frame is truncated.
+			// 已经读取了头部，因此帧被截断了
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+// readDelim reads the next delimiter-terminated frame.
+// readDelim 读取下一个以分隔符结尾的帧。
+func (f *FrameReader) readDelim() ([]byte, error) {
+	frame, err := f.rd.ReadSlice(f.delim)
+	if err == ErrBufferFull {
+		// The frame does not fit in the Reader's buffer:
+		// assemble it in f.buf from successive fragments.
+		// 帧无法放入Reader的缓冲区：在f.buf中拼装后续的片段
+		full := append(f.buf[:0], frame...)
+		for err == ErrBufferFull && len(full) <= f.max {
+			frame, err = f.rd.ReadSlice(f.delim)
+			full = append(full, frame...)
+		}
+		f.buf = full
+		frame = full
+	}
+	if err == nil {
+		frame = frame[:len(frame)-1] // drop delim
+	} else if (err != io.EOF && err != ErrBufferFull) || len(frame) == 0 {
+		return nil, err
+	}
+	// io.EOF with data: final frame without delimiter.
+	// 遇到io.EOF但有数据：最后一个帧缺少分隔符
+	if len(frame) > f.max {
+		f.err = ErrFrameTooLarge
+		return nil, f.err
+	}
+	return frame, nil
+}
+
+// FrameWriter writes length-prefixed or delimiter-terminated records
+// (frames) to a Writer. The header and payload are written into the
+// Writer's buffer; call Flush on the FrameWriter or the Writer to forward
+// them to the underlying io.Writer.
+// FrameWriter 向Writer写入长度前缀或者以分隔符结尾的记录（帧）。
+// 帧头和负载都写入Writer的缓冲区，需要调用Flush将它们转发到底层io.Writer。
+type FrameWriter struct {
+	wr    *Writer
+	kind  frameKind
+	hdr   int
+	delim byte
+	max   int
+}
+
+// NewVarintFrameWriter returns a FrameWriter that prefixes each frame with
+// its length encoded as an unsigned varint. If max <= 0, the size of wr's
+// buffer is used as the maximum frame size.
+// NewVarintFrameWriter 返回一个在每个帧前写入uvarint编码长度的FrameWriter。
+func NewVarintFrameWriter(wr *Writer, max int) *FrameWriter {
+	return newFrameWriter(wr, frameVarint, 0, 0, max)
+}
+
+// NewFixedFrameWriter returns a FrameWriter that prefixes each frame with
+// its big-endian length in headerLen bytes, which must be between 1 and 8.
+// If max <= 0, the size of wr's buffer is used as the maximum frame size.
+// NewFixedFrameWriter 返回一个在每个帧前写入headerLen字节大端序长度的FrameWriter。
+func NewFixedFrameWriter(wr *Writer, headerLen, max int) *FrameWriter {
+	if headerLen < 1 || headerLen > 8 {
+		panic("bufio: invalid frame header length")
+	}
+	return newFrameWriter(wr, frameFixed, headerLen, 0, max)
+}
+
+// NewDelimFrameWriter returns a FrameWriter that terminates each frame with
+// delim. If max <= 0, the size of wr's buffer is used as the maximum frame
+// size.
+// NewDelimFrameWriter 返回一个在每个帧后写入delim的FrameWriter。
+func NewDelimFrameWriter(wr *Writer, delim byte, max int) *FrameWriter {
+	return newFrameWriter(wr, frameDelim, 0, delim, max)
+}
+
+func newFrameWriter(wr *Writer, kind frameKind, hdr int, delim byte, max int) *FrameWriter {
+	if max <= 0 {
+		max = wr.Size()
+	}
+	return &FrameWriter{
+		wr:    wr,
+		kind:  kind,
+		hdr:   hdr,
+		delim: delim,
+		max:   max,
+	}
+}
+
+// WriteFrame writes p as a single frame. It returns ErrFrameTooLarge if p
+// exceeds the maximum frame size or cannot be described by the header, and
+// ErrFrameDelim if p contains the delimiter of a delimited frame; in those
+// cases nothing is written.
+// WriteFrame 将p作为一个帧写入。如果p超过最大帧大小或者无法用帧头表示，返回ErrFrameTooLarge；
+// 如果p包含分隔符，返回ErrFrameDelim；这两种情况下都不会写入任何数据。
+func (f *FrameWriter) WriteFrame(p []byte) error {
+	if len(p) > f.max {
+		return ErrFrameTooLarge
+	}
+	switch f.kind {
+	case frameVarint:
+		// Encode the header straight into the Writer's free space.
+		// 直接在Writer的空闲缓冲区中编码帧头
+		hdr := binary.AppendUvarint(f.wr.AvailableBuffer(), uint64(len(p)))
+		if _, err := f.wr.Write(hdr); err != nil {
+			return err
+		}
+	case frameFixed:
+		if f.hdr < 8 && uint64(len(p)) >= 1<<(8*f.hdr) {
+			return ErrFrameTooLarge
+		}
+		hdr := f.wr.AvailableBuffer()
+		for i := f.hdr - 1; i >= 0; i-- {
+			hdr = append(hdr, byte(uint64(len(p))>>(8*i)))
+		}
+		if _, err := f.wr.Write(hdr); err != nil {
+			return err
+		}
+	case frameDelim:
+		if bytes.IndexByte(p, f.delim) >= 0 {
+			return ErrFrameDelim
+		}
+		if _, err := f.wr.Write(p); err != nil {
+			return err
+		}
+		return f.wr.WriteByte(f.delim)
+	}
+	_, err := f.wr.Write(p)
+	return err
+}
+
+// Flush writes any buffered frames to the underlying io.Writer.
+// Flush 将所有缓冲的帧写入底层io.Writer。
+func (f *FrameWriter) Flush() error {
+	return f.wr.Flush()
+}
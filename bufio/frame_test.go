@@ -0,0 +1,207 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var framings = []struct {
+	name      string
+	newWriter func(w *Writer, max int) *FrameWriter
+	newReader func(r *Reader, max int) *FrameReader
+}{
+	{
+		"varint",
+		func(w *Writer, max int) *FrameWriter { return NewVarintFrameWriter(w, max) },
+		func(r *Reader, max int) *FrameReader { return NewVarintFrameReader(r, max) },
+	},
+	{
+		"fixed1",
+		func(w *Writer, max int) *FrameWriter { return NewFixedFrameWriter(w, 1, max) },
+		func(r *Reader, max int) *FrameReader { return NewFixedFrameReader(r, 1, max) },
+	},
+	{
+		"fixed4",
+		func(w *Writer, max int) *FrameWriter { return NewFixedFrameWriter(w, 4, max) },
+		func(r *Reader, max int) *FrameReader { return NewFixedFrameReader(r, 4, max) },
+	},
+	{
+		"delim",
+		func(w *Writer, max int) *FrameWriter { return NewDelimFrameWriter(w, '\n', max) },
+		func(r *Reader, max int) *FrameReader { return NewDelimFrameReader(r, '\n', max) },
+	},
+}
+
+// The frames are written and read with 16-byte buffers, so that some of
+// them have to be assembled from several fills.
+var frameTests = [][]string{
+	{},
+	{""},
+	{"a", "", "bc"},
+	{"exactly sixteen!", "seventeen bytes!!"},
+	{strings.Repeat("long frame ", 20), "x", strings.Repeat("y", 100)},
+}
+
+func TestFrameRoundTrip(t *testing.T) {
+	for _, f := range framings {
+		for _, frames := range frameTests {
+			var out bytes.Buffer
+			fw := f.newWriter(NewWriterSize(&out, 16), 1000)
+			for _, p := range frames {
+				if err := fw.WriteFrame([]byte(p)); err != nil {
+					t.Fatalf("%s: WriteFrame(%q) = %v", f.name, p, err)
+				}
+			}
+			if err := fw.Flush(); err != nil {
+				t.Fatalf("%s: Flush = %v", f.name, err)
+			}
+
+			r := NewReaderSize(iotest.OneByteReader(bytes.NewReader(out.Bytes())), 16)
+			fr := f.newReader(r, 1000)
+			for _, want := range frames {
+				got, err := fr.ReadFrame()
+				if err != nil || string(got) != want {
+					t.Fatalf("%s: ReadFrame = %q, %v, want %q, nil", f.name, got, err, want)
+				}
+			}
+			if got, err := fr.ReadFrame(); err != io.EOF {
+				t.Fatalf("%s: ReadFrame at end = %q, %v, want io.EOF", f.name, got, err)
+			}
+		}
+	}
+}
+
+var readFrameErrorTests = []struct {
+	name   string
+	input  string
+	reader func(r *Reader) *FrameReader
+	frames []string // read successfully before the error
+	err    error
+}{
+	{
+		"varint truncated header",
+		"\x01a\x80",
+		func(r *Reader) *FrameReader { return NewVarintFrameReader(r, 0) },
+		[]string{"a"},
+		io.ErrUnexpectedEOF,
+	},
+	{
+		"varint truncated payload",
+		"\x05abc",
+		func(r *Reader) *FrameReader { return NewVarintFrameReader(r, 0) },
+		nil,
+		io.ErrUnexpectedEOF,
+	},
+	{
+		"varint truncated large payload",
+		"\x20abc",
+		func(r *Reader) *FrameReader { return NewVarintFrameReader(r, 100) },
+		nil,
+		io.ErrUnexpectedEOF,
+	},
+	{
+		"varint large payload missing",
+		"\x01a\x20",
+		func(r *Reader) *FrameReader { return NewVarintFrameReader(r, 100) },
+		[]string{"a"},
+		io.ErrUnexpectedEOF,
+	},
+	{
+		"fixed large payload missing",
+		"\x00\x20",
+		func(r *Reader) *FrameReader { return NewFixedFrameReader(r, 2, 100) },
+		nil,
+		io.ErrUnexpectedEOF,
+	},
+	{
+		"varint too large",
+		"\x11" + strings.Repeat("a", 17),
+		func(r *Reader) *FrameReader { return NewVarintFrameReader(r, 0) },
+		nil,
+		ErrFrameTooLarge,
+	},
+	{
+		"fixed truncated header",
+		"\x00\x01a\x00",
+		func(r *Reader) *FrameReader { return NewFixedFrameReader(r, 2, 0) },
+		[]string{"a"},
+		io.ErrUnexpectedEOF,
+	},
+	{
+		"fixed too large",
+		"\x00\x00\x01\x00",
+		func(r *Reader) *FrameReader { return NewFixedFrameReader(r, 4, 255) },
+		nil,
+		ErrFrameTooLarge,
+	},
+	{
+		"delim without final delimiter",
+		"a\nbc",
+		func(r *Reader) *FrameReader { return NewDelimFrameReader(r, '\n', 0) },
+		[]string{"a", "bc"},
+		io.EOF,
+	},
+	{
+		"delim too large",
+		"a\n" + strings.Repeat("b", 40) + "\nc\n",
+		func(r *Reader) *FrameReader { return NewDelimFrameReader(r, '\n', 32) },
+		[]string{"a"},
+		ErrFrameTooLarge,
+	},
+}
+
+func TestReadFrameErrors(t *testing.T) {
+	for _, tt := range readFrameErrorTests {
+		fr := tt.reader(NewReaderSize(strings.NewReader(tt.input), 16))
+		for _, want := range tt.frames {
+			got, err := fr.ReadFrame()
+			if err != nil || string(got) != want {
+				t.Fatalf("%s: ReadFrame = %q, %v, want %q, nil", tt.name, got, err, want)
+			}
+		}
+		if _, err := fr.ReadFrame(); err != tt.err {
+			t.Errorf("%s: ReadFrame error = %v, want %v", tt.name, err, tt.err)
+		}
+		if tt.err == ErrFrameTooLarge {
+			// The stream can no longer be framed.
+			if _, err := fr.ReadFrame(); err != ErrFrameTooLarge {
+				t.Errorf("%s: ReadFrame after ErrFrameTooLarge = %v", tt.name, err)
+			}
+		}
+	}
+}
+
+var writeFrameErrorTests = []struct {
+	name   string
+	writer func(w *Writer) *FrameWriter
+	frame  string
+	err    error
+}{
+	{"larger than max", func(w *Writer) *FrameWriter { return NewVarintFrameWriter(w, 4) }, "abcde", ErrFrameTooLarge},
+	{"larger than buffer", func(w *Writer) *FrameWriter { return NewDelimFrameWriter(w, '\n', 0) }, strings.Repeat("a", 17), ErrFrameTooLarge},
+	{"header overflow", func(w *Writer) *FrameWriter { return NewFixedFrameWriter(w, 1, 1000) }, strings.Repeat("a", 256), ErrFrameTooLarge},
+	{"delimiter in frame", func(w *Writer) *FrameWriter { return NewDelimFrameWriter(w, '\n', 0) }, "a\nb", ErrFrameDelim},
+}
+
+func TestWriteFrameErrors(t *testing.T) {
+	for _, tt := range writeFrameErrorTests {
+		var out bytes.Buffer
+		w := NewWriterSize(&out, 16)
+		fw := tt.writer(w)
+		if err := fw.WriteFrame([]byte(tt.frame)); err != tt.err {
+			t.Errorf("%s: WriteFrame = %v, want %v", tt.name, err, tt.err)
+		}
+		fw.Flush()
+		if out.Len() != 0 || w.Buffered() != 0 {
+			t.Errorf("%s: rejected frame wrote %q", tt.name, out.Bytes())
+		}
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"bufio/bufiotest"
+	"bytes"
+	"io"
+	"testing"
+	"unicode/utf8"
+)
+
+// The fuzz harness below drives a Reader with random sequences of read
+// operations over a bufiotest.Reader and compares every
+// result with a reference model of the stream. After each operation it
+// also checks the invariants that tie the Reader's internal state
+// (r, w, lastByte and lastRuneSize) to the model.
+
+// fuzzChunks turns sched into the Chunks of a bufiotest.Reader. Entries
+// that are multiples of 8 become zero-length reads, in runs short enough
+// that the Reader never gives up with io.ErrNoProgress.
+func fuzzChunks(sched []byte, oneByte bool) []int {
+	if len(sched) == 0 {
+		if oneByte {
+			return []int{1}
+		}
+		return nil
+	}
+	var chunks []int
+	empty := 0 // zero-length reads in a row
+	for _, c := range sched {
+		switch {
+		case c%8 == 0 && empty < MaxConsecutiveEmptyReads/4:
+			empty++
+			chunks = append(chunks, 0)
+		case oneByte:
+			empty = 0
+			chunks = append(chunks, 1)
+		default:
+			empty = 0
+			chunks = append(chunks, 1+int(c))
+		}
+	}
+	if empty == len(chunks) {
+		chunks = append(chunks, 1)
+	}
+	return chunks
+}
+
+// readerModel is the reference model: the position in the stream and
+// which unread operations the documentation allows.
+type readerModel struct {
+	data []byte
+	stop int   // the stream ends after data[:stop]
+	err  error // with this error; io.EOF for a clean end
+	pos  int
+
+	lastByte     bool // UnreadByte is allowed
+	lastRuneSize int  // UnreadRune is allowed if > 0
+
+	// UnreadRune is documented to work only right after ReadRune.
+	// A read that returns no data keeps lastRuneSize but may refill
+	// and slide the buffer, after which UnreadRune may fail.
+	runeUnsure bool
+}
+
+func (m *readerModel) invalidate() {
+	m.lastByte = false
+	m.lastRuneSize = 0
+	m.runeUnsure = false
+}
+
+// noData records a read operation that returned no data.
+func (m *readerModel) noData() {
+	if m.lastRuneSize > 0 {
+		m.runeUnsure = true
+	}
+}
+
+// consume checks that p is the next part of the stream and advances
+// past it. Like the Reader, it only records a last byte for UnreadByte
+// if p is not empty.
+func (t *fuzzTest) consume(op string, p []byte) {
+	m := t.m
+	if m.pos+len(p) > m.stop || !bytes.Equal(p, m.data[m.pos:m.pos+len(p)]) {
+		t.Fatalf("%s: got %q at offset %d, want prefix of %q", op, p, m.pos, m.data[m.pos:m.stop])
+	}
+	m.pos += len(p)
+	if len(p) > 0 {
+		m.lastByte = true
+		m.lastRuneSize = 0
+		m.runeUnsure = false
+	} else {
+		m.noData()
+	}
+}
+
+// checkEnd checks that err is the error that ends the stream and that
+// the stream has indeed been consumed.
+func (t *fuzzTest) checkEnd(op string, err error) {
+	if err != t.m.err {
+		t.Fatalf("%s: got error %v, want %v", op, err, t.m.err)
+	}
+	if t.m.pos != t.m.stop {
+		t.Fatalf("%s: got error %v at offset %d, before end of stream at %d", op, err, t.m.pos, t.m.stop)
+	}
+}
+
+type fuzzTest struct {
+	*testing.T
+	b *Reader
+	m *readerModel
+}
+
+// checkInvariants checks the Reader's internal state against the model.
+func (t *fuzzTest) checkInvariants(op string) {
+	b, m := t.b, t.m
+	buf, r, w, lastByte, lastRuneSize := ReaderState(b)
+	if r < 0 || r > w || w > len(buf) {
+		t.Fatalf("%s: bad positions r=%d w=%d len(buf)=%d", op, r, w, len(buf))
+	}
+	if n := w - r; m.pos+n > m.stop || !bytes.Equal(buf[r:w], m.data[m.pos:m.pos+n]) {
+		t.Fatalf("%s: buffered %q at offset %d does not match the stream", op, buf[r:w], m.pos)
+	}
+	if b.Buffered() != w-r {
+		t.Fatalf("%s: Buffered() = %d, want %d", op, b.Buffered(), w-r)
+	}
+	if lastByte < -1 || lastByte > 0xff {
+		t.Fatalf("%s: bad lastByte %d", op, lastByte)
+	}
+	if (lastByte >= 0) != m.lastByte {
+		t.Fatalf("%s: lastByte = %d, model allows UnreadByte: %v", op, lastByte, m.lastByte)
+	}
+	if lastByte >= 0 && byte(lastByte) != m.data[m.pos-1] {
+		t.Fatalf("%s: lastByte = %q, want %q", op, lastByte, m.data[m.pos-1])
+	}
+	if lastRuneSize != -1 && (lastRuneSize < 1 || lastRuneSize > utf8.UTFMax) {
+		t.Fatalf("%s: bad lastRuneSize %d", op, lastRuneSize)
+	}
+	if max(lastRuneSize, 0) != m.lastRuneSize {
+		t.Fatalf("%s: lastRuneSize = %d, want %d", op, lastRuneSize, m.lastRuneSize)
+	}
+	if lastRuneSize > r && !m.runeUnsure {
+		t.Fatalf("%s: lastRuneSize = %d with r=%d right after ReadRune", op, lastRuneSize, r)
+	}
+}
+
+func (t *fuzzTest) read(n int) {
+	p := make([]byte, n)
+	got, err := t.b.Read(p)
+	if got < 0 || got > n {
+		t.Fatalf("Read(%d) = %d", n, got)
+	}
+	if got > 0 {
+		t.consume("Read", p[:got])
+	} else if n > 0 {
+		t.m.noData()
+	}
+	if err != nil {
+		// A large Read goes straight to the underlying reader,
+		// so it may return data together with the final error.
+		t.checkEnd("Read", err)
+	}
+}
+
+func (t *fuzzTest) readByte() {
+	c, err := t.b.ReadByte()
+	if err != nil {
+		t.m.lastRuneSize, t.m.runeUnsure = 0, false
+		t.checkEnd("ReadByte", err)
+		return
+	}
+	t.consume("ReadByte", []byte{c})
+}
+
+func (t *fuzzTest) unreadByte() {
+	err := t.b.UnreadByte()
+	if t.m.lastByte != (err == nil) {
+		t.Fatalf("UnreadByte: got %v, model allows it: %v", err, t.m.lastByte)
+	}
+	if err != nil {
+		if err != ErrInvalidUnreadByte {
+			t.Fatalf("UnreadByte: got %v", err)
+		}
+		return
+	}
+	t.m.pos--
+	t.m.invalidate()
+}
+
+func (t *fuzzTest) readRune() {
+	m := t.m
+	r, size, err := t.b.ReadRune()
+	if err != nil {
+		m.lastRuneSize, m.runeUnsure = 0, false
+		t.checkEnd("ReadRune", err)
+		return
+	}
+	wr, wsize := utf8.DecodeRune(m.data[m.pos:min(m.pos+utf8.UTFMax, m.stop)])
+	if r != wr || size != wsize {
+		t.Fatalf("ReadRune = %q, %d; want %q, %d", r, size, wr, wsize)
+	}
+	t.consume("ReadRune", m.data[m.pos:m.pos+size])
+	m.lastRuneSize = size
+}
+
+func (t *fuzzTest) unreadRune() {
+	err := t.b.UnreadRune()
+	if !t.m.runeUnsure && (t.m.lastRuneSize > 0) != (err == nil) {
+		t.Fatalf("UnreadRune: got %v, model allows it: %v", err, t.m.lastRuneSize > 0)
+	}
+	if err != nil {
+		if err != ErrInvalidUnreadRune {
+			t.Fatalf("UnreadRune: got %v", err)
+		}
+		return
+	}
+	t.m.pos -= t.m.lastRuneSize
+	t.m.invalidate()
+}
+
+func (t *fuzzTest) peek(n int) {
+	m := t.m
+	p, err := t.b.Peek(n)
+	m.invalidate()
+	if m.pos+len(p) > m.stop || !bytes.Equal(p, m.data[m.pos:m.pos+len(p)]) {
+		t.Fatalf("Peek(%d) = %q, want prefix of %q", n, p, m.data[m.pos:m.stop])
+	}
+	switch {
+	case len(p) == n && err == nil:
+	case len(p) < n && err == ErrBufferFull && n > t.b.Size():
+	case len(p) < n && err != nil && m.pos+len(p) == m.stop:
+		if err != m.err {
+			t.Fatalf("Peek(%d): got error %v, want %v", n, err, m.err)
+		}
+	default:
+		t.Fatalf("Peek(%d) = %q, %v at offset %d of %d", n, p, err, m.pos, m.stop)
+	}
+}
+
+func (t *fuzzTest) discard(n int) {
+	m := t.m
+	d, err := t.b.Discard(n)
+	if n > 0 {
+		m.invalidate()
+	}
+	if d < 0 || d > n || m.pos+d > m.stop {
+		t.Fatalf("Discard(%d) = %d at offset %d of %d", n, d, m.pos, m.stop)
+	}
+	m.pos += d
+	if d < n || err != nil {
+		if d == n {
+			t.Fatalf("Discard(%d) = %d, %v; want no error", n, d, err)
+		}
+		t.checkEnd("Discard", err)
+	}
+}
+
+func (t *fuzzTest) readSlice(delim byte) {
+	line, err := t.b.ReadSlice(delim)
+	i := bytes.IndexByte(line, delim)
+	switch {
+	case err == nil:
+		if i != len(line)-1 {
+			t.Fatalf("ReadSlice(%q) = %q, want exactly one delimiter, at the end", delim, line)
+		}
+	case i >= 0:
+		t.Fatalf("ReadSlice(%q) = %q, %v; want no delimiter with error", delim, line, err)
+	case err == ErrBufferFull:
+		if len(line) != t.b.Size() {
+			t.Fatalf("ReadSlice(%q) = %d bytes, ErrBufferFull; want %d bytes", delim, len(line), t.b.Size())
+		}
+	}
+	t.consume("ReadSlice", line)
+	if err != nil && err != ErrBufferFull {
+		t.checkEnd("ReadSlice", err)
+	}
+}
+
+func (t *fuzzTest) writeTo(readerFrom bool) {
+	m := t.m
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	if !readerFrom {
+		w = struct{ io.Writer }{&buf} // hide bytes.Buffer.ReadFrom
+	}
+	n, err := t.b.WriteTo(w)
+	m.invalidate()
+	if n != int64(buf.Len()) {
+		t.Fatalf("WriteTo = %d, but wrote %d bytes", n, buf.Len())
+	}
+	t.consume("WriteTo", buf.Bytes())
+	m.invalidate()
+	want := m.err
+	if want == io.EOF {
+		want = nil
+	}
+	if err != want || m.pos != m.stop {
+		t.Fatalf("WriteTo = %d, %v at offset %d of %d; want %v at end", n, err, m.pos, m.stop, want)
+	}
+}
+
+func FuzzReader(f *testing.F) {
+	f.Add([]byte("hello, world\nline two\r\n"), []byte{0, 3, 1, 0, 2, 0, 7, '\n', 5, 4}, []byte{1, 2, 3}, byte(0))
+	f.Add([]byte("héllo wörld ☺ \xff\xfe"), []byte{3, 0, 4, 0, 3, 0, 2, 0, 3, 0, 4, 0, 6, 2, 8, 0}, []byte{0, 1}, byte(1))
+	f.Add(bytes.Repeat([]byte("0123456789"), 10), []byte{7, '#', 7, '#', 5, 40, 6, 30, 0, 70}, []byte{0, 0, 50}, byte(6))
+	f.Add([]byte("abc"), []byte{1, 0, 1, 0, 1, 0, 1, 0, 2, 0, 2, 0}, []byte{8}, byte(2))
+	// ReadRune, then a ReadSlice that refills the buffer but returns nothing.
+	f.Add([]byte("aé"), []byte{1, 0, 3, 0, 7, '#', 4, 0, 2, 0, 1, 0}, []byte{2}, byte(0))
+	f.Fuzz(func(t *testing.T, data, ops, sched []byte, mode byte) {
+		// The stream ends after FailAfter bytes with Err, either on its
+		// own or together with the last chunk.
+		src := &bufiotest.Reader{
+			R:           bytes.NewReader(data),
+			Chunks:      fuzzChunks(sched, mode&1 != 0),
+			FailAfter:   int64(len(data)),
+			Err:         io.EOF,
+			ErrWithData: mode&2 != 0,
+		}
+		if mode&4 != 0 && len(sched) > 0 {
+			// Fail mid-stream.
+			src.FailAfter = int64(sched[0]) % int64(len(data)+1)
+			src.Err = bufiotest.ErrInjected
+		}
+		b := NewReaderSize(src, MinReadBufferSize+int(mode>>3))
+		ft := &fuzzTest{T: t, b: b, m: &readerModel{data: data, stop: int(src.FailAfter), err: src.Err}}
+		ft.checkInvariants("NewReader")
+
+		for i := 0; i+1 < len(ops); i += 2 {
+			op, arg := ops[i]%10, ops[i+1]
+			var name string
+			switch op {
+			case 0:
+				name = "Read"
+				ft.read(int(arg))
+			case 1:
+				name = "ReadByte"
+				ft.readByte()
+			case 2:
+				name = "UnreadByte"
+				ft.unreadByte()
+			case 3:
+				name = "ReadRune"
+				ft.readRune()
+			case 4:
+				name = "UnreadRune"
+				ft.unreadRune()
+			case 5:
+				name = "Peek"
+				ft.peek(int(arg))
+			case 6:
+				name = "Discard"
+				ft.discard(int(arg))
+			case 7:
+				name = "ReadSlice"
+				ft.readSlice(arg)
+			case 8, 9:
+				name = "WriteTo"
+				ft.writeTo(op == 8)
+			}
+			ft.checkInvariants(name)
+		}
+	})
+}
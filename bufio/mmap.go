@@ -0,0 +1,278 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+var errMapTooLarge = errors.New("bufio: file too large to map")
+
+// MappedReader provides the read methods of Reader for a file mapped into
+// memory. Its buffer is the whole mapping, so Peek, ReadSlice and ReadLine
+// return views into the file without copying and never fail with
+// ErrBufferFull unless the request exceeds the file itself.
+// MappedReader 为映射到内存的文件提供与Reader相同的读取方法。
+// 它的缓冲区就是整个映射区域，因此Peek、ReadSlice和ReadLine返回文件的视图，
+// 不做拷贝，并且除非请求超过文件本身，否则不会因为ErrBufferFull而失败。
+//
+// The mapping is read-only. Slices returned by MappedReader stay valid
+// until Close, not just until the next read, but they must not be
+// modified. If the file is truncated while it is mapped, accessing the
+// lost pages crashes the program, as with any memory-mapped file.
+// 映射区域是只读的。MappedReader返回的切片在Close之前一直有效，而不仅仅是到下一次读取之前，
+// 但不能修改这些切片。如果文件在映射期间被截断，访问丢失的页面会导致程序崩溃。
+//
+// On systems without mmap support the file is read into memory instead.
+// 在不支持mmap的系统上，文件会被完整读入内存。
+type MappedReader struct {
+	buf          []byte
+	r            int // buf read position; the write position is len(buf)
+	lastByte     int // last byte read for UnreadByte; -1 means invalid
+	lastRuneSize int // size of last rune read for UnreadRune; -1 means invalid
+	mapped       bool
+}
+
+// NewMappedReader maps the current contents of f into memory and returns a
+// MappedReader over them. The mapping does not depend on f remaining open.
+// Call Close to release the mapping.
+// NewMappedReader 将f的当前内容映射到内存，并返回一个基于该映射的MappedReader。
+// 映射不依赖于f保持打开状态。调用Close释放映射。
+func NewMappedReader(f *os.File) (*MappedReader, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := fi.Size()
+	if int64(int(size)) != size {
+		return nil, errMapTooLarge
+	}
+	b := &MappedReader{lastByte: -1, lastRuneSize: -1}
+	if size == 0 {
+		// mmap rejects empty mappings.
+		// mmap 不允许长度为0的映射
+		return b, nil
+	}
+	b.buf, b.mapped, err = mmap(f, int(size))
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Close releases the mapping. Slices previously returned by b must not be
+// used after Close.
+// Close 释放映射。Close之后不能再使用b之前返回的切片。
+func (b *MappedReader) Close() error {
+	buf, mapped := b.buf, b.mapped
+	*b = MappedReader{lastByte: -1, lastRuneSize: -1}
+	if !mapped {
+		return nil
+	}
+	return munmap(buf)
+}
+
+// Size returns the size of the mapping in bytes.
+// Size 返回映射区域的大小（以字节为单位）。
+func (b *MappedReader) Size() int { return len(b.buf) }
+
+// Buffered returns the number of bytes that have not been read yet.
+// Buffered 返回尚未读取的字节数。
+func (b *MappedReader) Buffered() int { return len(b.buf) - b.r }
+
+// Peek returns the next n bytes without advancing the reader.
+// If fewer than n bytes remain, Peek returns them together with io.EOF,
+// or with ErrBufferFull if n is larger than the whole mapping.
+// Peek 返回接下来的n个字节，而不推进读取位置。
+// 如果剩余不足n个字节，Peek返回剩余的字节和io.EOF；如果n大于整个映射区域，则返回ErrBufferFull。
+func (b *MappedReader) Peek(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, ErrNegativeCount
+	}
+	b.lastByte = -1
+	b.lastRuneSize = -1
+	if avail := len(b.buf) - b.r; avail < n {
+		if n > len(b.buf) {
+			return b.buf[b.r:], ErrBufferFull
+		}
+		return b.buf[b.r:], io.EOF
+	}
+	return b.buf[b.r : b.r+n], nil
+}
+
+// Discard skips the next n bytes, returning the number of bytes discarded.
+// If Discard skips fewer than n bytes, it also returns io.EOF.
+// Discard 跳过接下来的n个字节，返回丢弃的字节数。如果跳过的字节数少于n，还会返回io.EOF。
+func (b *MappedReader) Discard(n int) (discarded int, err error) {
+	if n < 0 {
+		return 0, ErrNegativeCount
+	}
+	b.lastByte = -1
+	b.lastRuneSize = -1
+	if avail := len(b.buf) - b.r; avail < n {
+		n, err = avail, io.EOF
+	}
+	b.r += n
+	return n, err
+}
+
+// Read reads data into p, returning the number of bytes read.
+// At the end of the mapping it returns 0, io.EOF.
+// Read 将数据读入p，返回读入的字节数。到达映射区域末尾时返回0, io.EOF。
+func (b *MappedReader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if b.r == len(b.buf) {
+		return 0, io.EOF
+	}
+	n = copy(p, b.buf[b.r:])
+	b.r += n
+	b.lastByte = int(b.buf[b.r-1])
+	b.lastRuneSize = -1
+	return n, nil
+}
+
+// ReadByte reads and returns a single byte.
+// ReadByte 读取并返回一个单字节。
+func (b *MappedReader) ReadByte() (byte, error) {
+	b.lastRuneSize = -1
+	if b.r == len(b.buf) {
+		return 0, io.EOF
+	}
+	c := b.buf[b.r]
+	b.r++
+	b.lastByte = int(c)
+	return c, nil
+}
+
+// UnreadByte unreads the last byte. Only the most recently read byte can
+// be unread, and Peek and Discard are not considered read operations.
+// UnreadByte 取消读取最后一个字节。只能取消读取最近读取的字节，Peek和Discard不被视为读取操作。
+func (b *MappedReader) UnreadByte() error {
+	if b.lastByte < 0 || b.r == 0 {
+		return ErrInvalidUnreadByte
+	}
+	// The mapping is read-only, but the byte is still in place.
+	// 映射区域是只读的，但是该字节仍在原位置，直接回退即可
+	b.r--
+	b.lastByte = -1
+	b.lastRuneSize = -1
+	return nil
+}
+
+// ReadRune reads a single UTF-8 encoded Unicode character and returns the
+// rune and its size in bytes.
+// ReadRune 读取一个UTF-8编码的Unicode字符，并返回rune及其字节大小。
+func (b *MappedReader) ReadRune() (r rune, size int, err error) {
+	b.lastRuneSize = -1
+	if b.r == len(b.buf) {
+		return 0, 0, io.EOF
+	}
+	r, size = rune(b.buf[b.r]), 1
+	if r >= utf8.RuneSelf {
+		r, size = utf8.DecodeRune(b.buf[b.r:])
+	}
+	b.r += size
+	b.lastByte = int(b.buf[b.r-1])
+	b.lastRuneSize = size
+	return r, size, nil
+}
+
+// UnreadRune unreads the last rune. If the most recent method called on
+// b was not a ReadRune, UnreadRune returns an error.
+// UnreadRune 取消读取最后一个rune。如果最近调用的方法不是ReadRune，则返回错误。
+func (b *MappedReader) UnreadRune() error {
+	if b.lastRuneSize < 0 || b.r < b.lastRuneSize {
+		return ErrInvalidUnreadRune
+	}
+	b.r -= b.lastRuneSize
+	b.lastByte = -1
+	b.lastRuneSize = -1
+	return nil
+}
+
+// ReadSlice reads until the first occurrence of delim in the input,
+// returning a slice of the mapping. If ReadSlice reaches the end of the
+// mapping before finding a delimiter, it returns the remaining data and
+// io.EOF.
+// ReadSlice 读取到输入中第一个delim出现的位置，返回映射区域的切片。
+// 如果在找到分隔符之前到达映射区域末尾，则返回剩余的数据和io.EOF。
+func (b *MappedReader) ReadSlice(delim byte) (line []byte, err error) {
+	if i := bytes.IndexByte(b.buf[b.r:], delim); i >= 0 {
+		line = b.buf[b.r : b.r+i+1]
+	} else {
+		line = b.buf[b.r:]
+		err = io.EOF
+	}
+	b.r += len(line)
+	if i := len(line) - 1; i >= 0 {
+		b.lastByte = int(line[i])
+		b.lastRuneSize = -1
+	}
+	return line, err
+}
+
+// ReadLine returns a single line, not including the end-of-line bytes
+// ("\r\n" or "\n"). Since the whole file is buffered, isPrefix is always
+// false. ReadLine either returns a non-nil line or it returns an error,
+// never both.
+// ReadLine 返回单行，不包括行尾字节（"\r\n"或"\n"）。由于整个文件都已缓冲，isPrefix总是false。
+// ReadLine要么返回非nil行，要么返回错误，永远不会同时返回两者。
+func (b *MappedReader) ReadLine() (line []byte, isPrefix bool, err error) {
+	line, err = b.ReadSlice('\n')
+	if len(line) == 0 {
+		return nil, false, err
+	}
+	if line[len(line)-1] == '\n' {
+		drop := 1
+		if len(line) > 1 && line[len(line)-2] == '\r' {
+			drop = 2
+		}
+		line = line[:len(line)-drop]
+	}
+	return line, false, nil
+}
+
+// ReadBytes reads until the first occurrence of delim in the input,
+// returning a copy of the data up to and including the delimiter.
+// ReadBytes 读取到输入中第一个delim出现的位置，返回包含分隔符在内的数据的拷贝。
+func (b *MappedReader) ReadBytes(delim byte) ([]byte, error) {
+	line, err := b.ReadSlice(delim)
+	return bytes.Clone(line), err
+}
+
+// ReadString reads until the first occurrence of delim in the input,
+// returning a string containing the data up to and including the delimiter.
+// ReadString 读取到输入中第一个delim出现的位置，返回包含分隔符在内的数据的字符串。
+func (b *MappedReader) ReadString(delim byte) (string, error) {
+	line, err := b.ReadSlice(delim)
+	return string(line), err
+}
+
+// WriteTo implements io.WriterTo. It hands the rest of the mapping to w
+// in a single Write call.
+// WriteTo 实现了io.WriterTo。它通过一次Write调用将映射区域的剩余部分交给w。
+func (b *MappedReader) WriteTo(w io.Writer) (n int64, err error) {
+	b.lastByte = -1
+	b.lastRuneSize = -1
+	if b.r == len(b.buf) {
+		return 0, nil
+	}
+	rest := b.buf[b.r:]
+	m, err := w.Write(rest)
+	if m < 0 {
+		panic(errNegativeWrite)
+	}
+	b.r += m
+	if m < len(rest) && err == nil {
+		err = io.ErrShortWrite
+	}
+	return int64(m), err
+}
@@ -0,0 +1,26 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !unix
+
+package bufio
+
+import (
+	"io"
+	"os"
+)
+
+// mmap reads the first size bytes of f into memory, since the
+// system offers no mapping that MappedReader can use.
+func mmap(f *os.File, size int) (buf []byte, mapped bool, err error) {
+	buf = make([]byte, size)
+	if _, err := f.ReadAt(buf, 0); err != nil && err != io.EOF {
+		return nil, false, err
+	}
+	return buf, false, nil
+}
+
+func munmap(buf []byte) error {
+	return nil
+}
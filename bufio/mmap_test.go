@@ -0,0 +1,178 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// mapString returns a MappedReader over a temporary file holding s.
+func mapString(t *testing.T, s string) *MappedReader {
+	t.Helper()
+	f, err := os.CreateTemp(t.TempDir(), "mmap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(s); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewMappedReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The mapping does not depend on f.
+	f.Close()
+	t.Cleanup(func() { m.Close() })
+	return m
+}
+
+var mappedLineTests = []string{
+	"",
+	"\n",
+	"\n\n",
+	"one line",
+	"line1\r\nline2\nlast",
+	"trailing\r\n",
+	"bare\rcr\n",
+	strings.Repeat("a much longer line than the default buffer ", 200) + "\nend",
+}
+
+// TestMappedReaderLines checks that MappedReader splits lines as a Reader
+// does with a buffer large enough for every line.
+func TestMappedReaderLines(t *testing.T) {
+	for _, input := range mappedLineTests {
+		m := mapString(t, input)
+		if m.Size() != len(input) {
+			t.Errorf("%.20q: Size = %d, want %d", input, m.Size(), len(input))
+		}
+		r := NewReaderSize(strings.NewReader(input), len(input)+16)
+		for {
+			want, _, werr := r.ReadLine()
+			got, isPrefix, err := m.ReadLine()
+			if string(got) != string(want) || isPrefix || err != werr {
+				t.Fatalf("%.20q: ReadLine = %q, %v, %v, want %q, false, %v", input, got, isPrefix, err, want, werr)
+			}
+			if werr != nil {
+				break
+			}
+		}
+	}
+}
+
+var mappedPeekTests = []struct {
+	input   string
+	discard int
+	peek    int
+	want    string
+	err     error
+}{
+	{"abc", 0, 0, "", nil},
+	{"abc", 0, 2, "ab", nil},
+	{"abc", 0, 3, "abc", nil},
+	{"abc", 1, 3, "bc", io.EOF},
+	{"abc", 3, 1, "", io.EOF},
+	{"abc", 0, 4, "abc", ErrBufferFull},
+	{"abc", 0, -1, "", ErrNegativeCount},
+	{"", 0, 1, "", ErrBufferFull},
+}
+
+func TestMappedReaderPeek(t *testing.T) {
+	for _, tt := range mappedPeekTests {
+		m := mapString(t, tt.input)
+		if n, err := m.Discard(tt.discard); n != tt.discard || err != nil {
+			t.Fatalf("%q: Discard(%d) = %d, %v", tt.input, tt.discard, n, err)
+		}
+		got, err := m.Peek(tt.peek)
+		if string(got) != tt.want || err != tt.err {
+			t.Errorf("%q: Peek(%d) after Discard(%d) = %q, %v, want %q, %v", tt.input, tt.peek, tt.discard, got, err, tt.want, tt.err)
+		}
+		if m.Buffered() != len(tt.input)-tt.discard {
+			t.Errorf("%q: Peek advanced the reader", tt.input)
+		}
+	}
+}
+
+func TestMappedReaderDiscardPastEnd(t *testing.T) {
+	m := mapString(t, "abc")
+	if n, err := m.Discard(5); n != 3 || err != io.EOF {
+		t.Fatalf("Discard(5) = %d, %v, want 3, io.EOF", n, err)
+	}
+	if n, err := m.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Fatalf("Read at end = %d, %v, want 0, io.EOF", n, err)
+	}
+}
+
+func TestMappedReaderUnread(t *testing.T) {
+	m := mapString(t, "héllo, 世界")
+	var got []rune
+	for {
+		r, size, err := m.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Every rune can be unread and read again.
+		if err := m.UnreadRune(); err != nil {
+			t.Fatalf("UnreadRune after %q = %v", r, err)
+		}
+		if r2, size2, _ := m.ReadRune(); r2 != r || size2 != size {
+			t.Fatalf("ReadRune after UnreadRune = %q, %d, want %q, %d", r2, size2, r, size)
+		}
+		got = append(got, r)
+	}
+	if string(got) != "héllo, 世界" {
+		t.Fatalf("ReadRune sequence = %q", string(got))
+	}
+
+	m = mapString(t, "ab")
+	if err := m.UnreadByte(); err != ErrInvalidUnreadByte {
+		t.Errorf("UnreadByte at start = %v, want ErrInvalidUnreadByte", err)
+	}
+	m.ReadByte()
+	m.Peek(1)
+	if err := m.UnreadByte(); err != ErrInvalidUnreadByte {
+		t.Errorf("UnreadByte after Peek = %v, want ErrInvalidUnreadByte", err)
+	}
+	m.ReadByte()
+	if err := m.UnreadRune(); err != ErrInvalidUnreadRune {
+		t.Errorf("UnreadRune after ReadByte = %v, want ErrInvalidUnreadRune", err)
+	}
+	if err := m.UnreadByte(); err != nil {
+		t.Errorf("UnreadByte after ReadByte = %v", err)
+	}
+	if c, err := m.ReadByte(); c != 'b' || err != nil {
+		t.Errorf("ReadByte after UnreadByte = %q, %v, want 'b', nil", c, err)
+	}
+}
+
+func TestMappedReaderWriteTo(t *testing.T) {
+	m := mapString(t, "hello, world")
+	m.Discard(7)
+	var buf bytes.Buffer
+	if n, err := m.WriteTo(&buf); n != 5 || err != nil || buf.String() != "world" {
+		t.Fatalf("WriteTo = %d, %v, wrote %q", n, err, buf.String())
+	}
+	if n, err := m.WriteTo(&buf); n != 0 || err != nil {
+		t.Fatalf("WriteTo at end = %d, %v, want 0, nil", n, err)
+	}
+}
+
+// TestMappedReaderSlicesOutliveReads checks that slices stay valid across
+// reads, unlike those of a Reader.
+func TestMappedReaderSlicesOutliveReads(t *testing.T) {
+	m := mapString(t, "first\nsecond\n")
+	first, _ := m.ReadSlice('\n')
+	second, _ := m.ReadSlice('\n')
+	if string(first) != "first\n" || string(second) != "second\n" {
+		t.Fatalf("ReadSlice = %q, %q", first, second)
+	}
+}
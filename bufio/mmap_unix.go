@@ -0,0 +1,25 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build unix
+
+package bufio
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmap maps the first size bytes of f read-only into memory.
+func mmap(f *os.File, size int) (buf []byte, mapped bool, err error) {
+	buf, err = syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, false, os.NewSyscallError("mmap", err)
+	}
+	return buf, true, nil
+}
+
+func munmap(buf []byte) error {
+	return os.NewSyscallError("munmap", syscall.Munmap(buf))
+}
@@ -0,0 +1,234 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+var ErrCallCanceled = errors.New("bufio: pipelined call canceled")
+
+// Pipeline pipelines requests over a ReadWriter for protocols in which
+// the server answers requests in the order they were sent, such as Redis,
+// memcached or SMTP with PIPELINING. Requests are encoded into the Writer
+// by Send and go out in one batch on Flush; the responses are then read
+// from the Reader in the background and matched to their requests in
+// order.
+// Pipeline 在ReadWriter上以流水线方式发送请求，适用于服务端按请求发送顺序应答的协议，
+// 例如Redis、memcached或者支持PIPELINING的SMTP。Send将请求编码到Writer中，Flush将它们一次性发出；
+// 之后在后台从Reader中读取响应，并按顺序与请求一一对应。
+//
+// Send and Flush may be called from multiple goroutines.
+// Send 和 Flush 可以在多个goroutine中调用。
+type Pipeline struct {
+	rw     *ReadWriter
+	src    *pipelineSource
+	decode func(r *Reader) (any, error)
+
+	mu      sync.Mutex
+	unsent  []*Call // encoded into the Writer, not yet flushed
+	pending []*Call // flushed, waiting for their responses in order
+	reading bool    // a goroutine is reading responses
+	err     error   // sticky write or read error
+}
+
+// pipelineSource is the reader underlying the Reader of a Pipeline. It
+// records the first error of the connection, so that the Pipeline can tell
+// a failed read from a response that decode rejects, and keeps returning
+// it.
+// pipelineSource 是Pipeline的Reader底层的读取器。它记录连接的第一个错误，使Pipeline能够区分读取失败和被decode拒绝的响应，
+// 并且之后一直返回该错误。
+type pipelineSource struct {
+	rd  io.Reader
+	err error // only accessed by the goroutine reading responses
+}
+
+func (s *pipelineSource) Read(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	n, err := s.rd.Read(p)
+	s.err = err
+	return n, err
+}
+
+// A Call is a request sent through a Pipeline.
+// Call 是通过Pipeline发送的一个请求。
+type Call struct {
+	Value any   // the decoded response, valid once Done is closed
+	Err   error // the error of this call, valid once Done is closed
+
+	done chan struct{}
+	once sync.Once
+}
+
+// NewPipeline returns a Pipeline over rw. For each request, decode is
+// called to read the matching response from rw.Reader; its results become
+// the Value and Err of that request's Call. decode must consume exactly
+// one response, even when it reports an error, so that the following
+// responses stay matched to their requests.
+// NewPipeline 返回一个基于rw的Pipeline。对于每个请求，调用decode从rw.Reader中读取对应的响应；
+// 其结果成为该请求的Call的Value和Err。decode必须恰好消费一个响应，即使它报告了错误，
+// 这样后续的响应才能继续与它们的请求对应。
+//
+// If decode fails because reading from the connection failed, the
+// responses of the remaining requests cannot be read either: the read
+// error becomes the error of every pending request, and the Pipeline fails
+// as it does on a write error. The Pipeline takes over rw.Reader, which
+// must not be used or Reset while the Pipeline is in use.
+// 如果decode因为从连接读取失败而失败，其余请求的响应也都无法读取：该读取错误成为每个等待中请求的错误，
+// 并且Pipeline会像遇到写入错误时一样失败。Pipeline接管rw.Reader，在使用Pipeline期间不能使用或Reset它。
+func NewPipeline(rw *ReadWriter, decode func(r *Reader) (any, error)) *Pipeline {
+	src := &pipelineSource{rd: rw.Reader.rd}
+	rw.Reader.rd = src
+	return &Pipeline{rw: rw, src: src, decode: decode}
+}
+
+// Send queues a request by calling encode to write it into the Writer.
+// The request is not guaranteed to be sent until the next Flush. If encode
+// fails, or the Pipeline has failed to write before, the returned Call is
+// already done and carries the error.
+// Send 通过调用encode将请求写入Writer来排队一个请求。在下一次Flush之前不保证请求已经发出。
+// 如果encode失败，或者Pipeline之前已经写入失败，返回的Call已经完成并携带该错误。
+//
+// When encode fails, the part of the request it wrote is rolled back (see
+// Writer.Rollback). If that part no longer fits in the buffer and has
+// already been written out, the stream is corrupt, and the Pipeline fails
+// as it does on a write error.
+// encode失败时，它已写入的那部分请求会被回滚（参见Writer.Rollback）。如果这部分已经无法容纳在缓冲区中而被写出，
+// 数据流就被破坏了，Pipeline会像遇到写入错误时一样失败。
+func (p *Pipeline) Send(encode func(w *Writer) error) *Call {
+	c := &Call{done: make(chan struct{})}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		c.finish(nil, p.err)
+		return c
+	}
+	w := p.rw.Writer
+	w.Checkpoint()
+	if err := encode(w); err != nil {
+		c.finish(nil, err)
+		if rerr := w.Rollback(); rerr != nil {
+			p.fail(rerr)
+		}
+		return c
+	}
+	p.unsent = append(p.unsent, c)
+	return c
+}
+
+// Flush writes the queued requests to the underlying io.Writer and starts
+// reading their responses. If the write fails, the queued requests fail
+// with the same error; since it is no longer known which requests reached
+// the server, so do all later calls to Send.
+// Flush 将排队的请求写入底层io.Writer，并开始读取它们的响应。如果写入失败，排队的请求都以同样的错误失败；
+// 由于无法再知道哪些请求到达了服务端，之后所有的Send调用也都会失败。
+func (p *Pipeline) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	if err := p.rw.Flush(); err != nil {
+		p.fail(err)
+		return err
+	}
+	p.pending = append(p.pending, p.unsent...)
+	p.unsent = nil
+	if !p.reading && len(p.pending) > 0 {
+		p.reading = true
+		go p.readResponses()
+	}
+	return nil
+}
+
+// fail records the write or read error err, unless there already is one,
+// and fails the queued requests with it.
+// Called with p.mu held.
+// fail 记录写入或读取错误err（除非已经有了错误），并让排队的请求都以该错误失败。调用时必须持有p.mu。
+func (p *Pipeline) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+	for _, c := range p.unsent {
+		c.finish(nil, err)
+	}
+	p.unsent = nil
+}
+
+// readResponses decodes responses for the pending calls, in order, until
+// there are none left or reading fails.
+// readResponses 按顺序为等待中的Call解码响应，直到没有剩余的Call或者读取失败。
+func (p *Pipeline) readResponses() {
+	for {
+		p.mu.Lock()
+		if len(p.pending) == 0 {
+			p.reading = false
+			p.mu.Unlock()
+			return
+		}
+		c := p.pending[0]
+		p.mu.Unlock()
+
+		// A canceled call still has a response on the wire,
+		// which must be consumed.
+		// 已取消的Call在连接上仍然有响应，必须将其消费掉
+		v, err := p.decode(p.rw.Reader)
+
+		p.mu.Lock()
+		p.pending[0] = nil
+		p.pending = p.pending[1:]
+		if err != nil && p.src.err != nil && p.rw.Reader.Buffered() == 0 {
+			// The response was cut short by a read error, rather
+			// than rejected by decode.
+			// 响应被读取错误截断，而不是被decode拒绝
+			err = p.src.err
+			p.fail(err)
+			for _, pc := range p.pending {
+				pc.finish(nil, err)
+			}
+			p.pending = nil
+			p.reading = false
+			p.mu.Unlock()
+			c.finish(nil, err)
+			return
+		}
+		p.mu.Unlock()
+		c.finish(v, err)
+	}
+}
+
+// finish records the result of c, unless c is already done.
+// finish 记录c的结果，除非c已经完成。
+func (c *Call) finish(v any, err error) {
+	c.once.Do(func() {
+		c.Value = v
+		c.Err = err
+		close(c.done)
+	})
+}
+
+// Done returns a channel that is closed when the call is complete.
+// Done 返回一个在Call完成时关闭的通道。
+func (c *Call) Done() <-chan struct{} { return c.done }
+
+// Wait waits for the call to complete and returns its response and error.
+// Wait 等待Call完成并返回其响应和错误。
+func (c *Call) Wait() (any, error) {
+	<-c.done
+	return c.Value, c.Err
+}
+
+// Cancel completes c with ErrCallCanceled if it is not done yet.
+// The request has usually been sent already; its response is still read,
+// and then discarded.
+// Cancel 如果c还没有完成，则以ErrCallCanceled完成它。
+// 请求通常已经发出；它的响应仍会被读取，然后被丢弃。
+func (c *Call) Cancel() {
+	c.finish(nil, ErrCallCanceled)
+}
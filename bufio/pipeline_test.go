@@ -0,0 +1,218 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"bufio/bufiotest"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+var errBadResponse = errors.New("bad response")
+
+// decodeLine decodes a response of one line, rejecting "ERR".
+func decodeLine(r *Reader) (any, error) {
+	s, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if s == "ERR\n" {
+		return nil, errBadResponse
+	}
+	return s, nil
+}
+
+func sendLine(p *Pipeline, s string) *Call {
+	return p.Send(func(w *Writer) error {
+		_, err := w.WriteString(s + "\n")
+		return err
+	})
+}
+
+type pipelineResult struct {
+	value any
+	err   error
+}
+
+// The responses are delivered by a bufiotest.Reader configured by
+// chunks, failAfter, err and errWithData.
+var pipelineTests = []struct {
+	name        string
+	responses   string
+	chunks      []int
+	failAfter   int64
+	err         error
+	errWithData bool
+	want        []pipelineResult
+	after       error // error of later calls
+}{
+	{
+		name:      "in order",
+		responses: "1\n2\n3\n",
+		want:      []pipelineResult{{"1\n", nil}, {"2\n", nil}, {"3\n", nil}},
+	},
+	{
+		name:      "rejected response",
+		responses: "1\nERR\n3\n",
+		want:      []pipelineResult{{"1\n", nil}, {nil, errBadResponse}, {"3\n", nil}},
+	},
+	{
+		name:        "rejected response with EOF buffered",
+		responses:   "1\nERR\n3\n",
+		failAfter:   8,
+		err:         io.EOF,
+		errWithData: true,
+		want:        []pipelineResult{{"1\n", nil}, {nil, errBadResponse}, {"3\n", nil}},
+	},
+	{
+		name:      "read error between responses",
+		responses: "1\n2\n3\n",
+		failAfter: 2,
+		err:       bufiotest.ErrInjected,
+		want:      []pipelineResult{{"1\n", nil}, {nil, bufiotest.ErrInjected}, {nil, bufiotest.ErrInjected}},
+		after:     bufiotest.ErrInjected,
+	},
+	{
+		name:      "read error within response",
+		responses: "1\n2\n3\n",
+		chunks:    []int{1},
+		failAfter: 3,
+		err:       bufiotest.ErrInjected,
+		want:      []pipelineResult{{"1\n", nil}, {nil, bufiotest.ErrInjected}, {nil, bufiotest.ErrInjected}},
+		after:     bufiotest.ErrInjected,
+	},
+	{
+		name:        "read error with data",
+		responses:   "1\n2\n3\n",
+		failAfter:   4,
+		err:         bufiotest.ErrInjected,
+		errWithData: true,
+		want:        []pipelineResult{{"1\n", nil}, {"2\n", nil}, {nil, bufiotest.ErrInjected}},
+		after:       bufiotest.ErrInjected,
+	},
+	{
+		name:      "connection closed",
+		responses: "1\n",
+		want:      []pipelineResult{{"1\n", nil}, {nil, io.EOF}, {nil, io.EOF}},
+		after:     io.EOF,
+	},
+}
+
+func TestPipeline(t *testing.T) {
+	for _, tt := range pipelineTests {
+		var requests strings.Builder
+		responses := &bufiotest.Reader{
+			R:           strings.NewReader(tt.responses),
+			Chunks:      tt.chunks,
+			FailAfter:   tt.failAfter,
+			Err:         tt.err,
+			ErrWithData: tt.errWithData,
+		}
+		p := NewPipeline(NewReadWriter(NewReader(responses), NewWriter(&requests)), decodeLine)
+		var calls []*Call
+		for i := range tt.want {
+			calls = append(calls, sendLine(p, string(rune('a'+i))))
+		}
+		if err := p.Flush(); err != nil {
+			t.Fatalf("%s: Flush = %v", tt.name, err)
+		}
+		for i, c := range calls {
+			v, err := c.Wait()
+			if v != tt.want[i].value || err != tt.want[i].err {
+				t.Errorf("%s: call %d = %v, %v, want %v, %v", tt.name, i, v, err, tt.want[i].value, tt.want[i].err)
+			}
+		}
+		if requests.String() != "a\nb\nc\n" {
+			t.Errorf("%s: requests = %q", tt.name, requests.String())
+		}
+
+		// A read error is sticky.
+		c := sendLine(p, "d")
+		if tt.after != nil {
+			if _, err := c.Wait(); err != tt.after {
+				t.Errorf("%s: Send after read error = %v, want %v", tt.name, err, tt.after)
+			}
+			if err := p.Flush(); err != tt.after {
+				t.Errorf("%s: Flush after read error = %v, want %v", tt.name, err, tt.after)
+			}
+			continue
+		}
+		select {
+		case <-c.Done():
+			t.Errorf("%s: Send failed: %v", tt.name, c.Err)
+		default:
+		}
+	}
+}
+
+func TestPipelineCancel(t *testing.T) {
+	p := NewPipeline(NewReadWriter(NewReader(strings.NewReader("1\n2\n3\n")), NewWriter(io.Discard)), decodeLine)
+	calls := []*Call{sendLine(p, "a"), sendLine(p, "b"), sendLine(p, "c")}
+	calls[1].Cancel()
+	if err := p.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	// The response of the canceled call is consumed, so the next call
+	// gets its own response.
+	want := []pipelineResult{{"1\n", nil}, {nil, ErrCallCanceled}, {"3\n", nil}}
+	for i, c := range calls {
+		if v, err := c.Wait(); v != want[i].value || err != want[i].err {
+			t.Errorf("call %d = %v, %v, want %v, %v", i, v, err, want[i].value, want[i].err)
+		}
+	}
+}
+
+func TestPipelineWriteError(t *testing.T) {
+	w := &bufiotest.Writer{FailAfter: 1, Err: bufiotest.ErrInjected}
+	p := NewPipeline(NewReadWriter(NewReader(strings.NewReader("")), NewWriter(w)), decodeLine)
+	c := sendLine(p, "a")
+	if err := p.Flush(); err != bufiotest.ErrInjected {
+		t.Fatalf("Flush = %v, want %v", err, bufiotest.ErrInjected)
+	}
+	if _, err := c.Wait(); err != bufiotest.ErrInjected {
+		t.Fatalf("queued call = %v, want %v", err, bufiotest.ErrInjected)
+	}
+	if _, err := sendLine(p, "b").Wait(); err != bufiotest.ErrInjected {
+		t.Fatalf("Send after write error = %v, want %v", err, bufiotest.ErrInjected)
+	}
+}
+
+// TestPipelineRollback checks that a request that fails to encode leaves
+// nothing in the stream, unless it has already been flushed.
+func TestPipelineRollback(t *testing.T) {
+	var requests strings.Builder
+	p := NewPipeline(NewReadWriter(NewReader(strings.NewReader("1\n2\n")), NewWriterSize(&requests, 16)), decodeLine)
+	partial := func(s string) func(w *Writer) error {
+		return func(w *Writer) error {
+			w.WriteString(s)
+			return bufiotest.ErrInjected
+		}
+	}
+	calls := []*Call{sendLine(p, "a"), p.Send(partial("half")), sendLine(p, "b")}
+	if err := p.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := []pipelineResult{{"1\n", nil}, {nil, bufiotest.ErrInjected}, {"2\n", nil}}
+	for i, c := range calls {
+		if v, err := c.Wait(); v != want[i].value || err != want[i].err {
+			t.Errorf("call %d = %v, %v, want %v, %v", i, v, err, want[i].value, want[i].err)
+		}
+	}
+	if requests.String() != "a\nb\n" {
+		t.Errorf("requests = %q, want %q", requests.String(), "a\nb\n")
+	}
+
+	// Part of a request larger than the buffer is already flushed.
+	c := p.Send(partial(strings.Repeat("z", 40)))
+	if _, err := c.Wait(); err != bufiotest.ErrInjected {
+		t.Errorf("large call = %v, want %v", err, bufiotest.ErrInjected)
+	}
+	if err := p.Flush(); err != ErrCheckpointFlushed {
+		t.Errorf("Flush after flushed partial request = %v, want %v", err, ErrCheckpointFlushed)
+	}
+}
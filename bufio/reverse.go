@@ -0,0 +1,220 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+import (
+	"bytes"
+	"io"
+)
+
+// ReverseReader reads the lines of an io.ReaderAt from last to first,
+// as needed by tools like tail. It buffers the input backwards from the
+// end, so only the part of the input that is actually returned is read.
+// ReverseReader 从后往前读取io.ReaderAt中的行，满足tail之类工具的需要。
+// 它从末尾开始向前缓冲输入，因此只会读取实际返回的那部分输入。
+type ReverseReader struct {
+	buf []byte
+	ra  io.ReaderAt
+	off int64 // input offset of buf[0]
+	n   int   // buf[:n] holds input bytes [off, off+n)
+	end int64 // input before end has not been returned yet
+
+	// A line longer than buf is returned front to back in fragments
+	// covering input bytes [next, stop).
+	// 比buf更长的行按从前往后的顺序分片返回，覆盖输入的[next, stop)字节
+	long       bool
+	next, stop int64
+}
+
+// NewReverseReaderSize returns a new ReverseReader reading the first size
+// bytes of ra, whose buffer has at least the specified size.
+// NewReverseReaderSize 返回一个读取ra前size个字节的ReverseReader，其缓冲区至少具有指定的大小。
+func NewReverseReaderSize(ra io.ReaderAt, size int64, bufSize int) *ReverseReader {
+	if bufSize < minReadBufferSize {
+		bufSize = minReadBufferSize
+	}
+	return &ReverseReader{
+		buf: make([]byte, bufSize),
+		ra:  ra,
+		off: size,
+		end: size,
+	}
+}
+
+// NewReverseReader returns a new ReverseReader reading the first size bytes
+// of ra, whose buffer has the default size.
+// NewReverseReader 返回一个读取ra前size个字节的ReverseReader，其缓冲区具有默认大小。
+func NewReverseReader(ra io.ReaderAt, size int64) *ReverseReader {
+	return NewReverseReaderSize(ra, size, defaultBufSize)
+}
+
+// ReadLine returns the line preceding the previously returned one,
+// starting with the last line of the input, and io.EOF once the first
+// line has been returned. The line does not include the line end
+// ("\r\n" or "\n"), and a final line end at the end of the input does not
+// start an empty line, so the lines are exactly those returned by
+// Reader.ReadLine, in reverse order.
+// ReadLine 返回上一次返回的行之前的那一行，从输入的最后一行开始，返回第一行之后再调用则返回io.EOF。
+// 返回的行不包括行尾（"\r\n"或"\n"），输入末尾的行尾也不会产生一个空行，
+// 因此返回的行与Reader.ReadLine返回的行完全相同，只是顺序相反。
+//
+// If the line is too long for the buffer, isPrefix is set and, just like
+// with Reader.ReadLine, the beginning of the line is returned; the rest of
+// the line is returned by the following calls, with isPrefix false for
+// the last fragment. The returned buffer is only valid until the next call
+// to ReadLine. ReadLine either returns a non-nil line or it returns an
+// error, never both.
+// 如果行对于缓冲区太长，则设置isPrefix，并且和Reader.ReadLine一样先返回行的开头；
+// 行的其余部分由之后的调用返回，返回最后一个片段时isPrefix为false。
+// 返回的缓冲区仅在下一次调用ReadLine之前有效。ReadLine要么返回非nil行，要么返回错误，永远不会同时返回两者。
+func (b *ReverseReader) ReadLine() (line []byte, isPrefix bool, err error) {
+	if b.long {
+		return b.readLong()
+	}
+	if b.end == 0 {
+		return nil, false, io.EOF
+	}
+
+	// Drop the line end, if any. Only the last line of the input
+	// can lack one.
+	// 去掉行尾（如果有的话）。只有输入的最后一行可能没有行尾。
+	lineEnd := b.end
+	if c, err := b.byteAt(lineEnd - 1); err != nil {
+		return nil, false, err
+	} else if c == '\n' {
+		lineEnd--
+		if lineEnd > 0 {
+			if c, err := b.byteAt(lineEnd - 1); err != nil {
+				return nil, false, err
+			} else if c == '\r' {
+				lineEnd--
+			}
+		}
+	}
+
+	// Search backwards for the line end of the preceding line.
+	// 向前查找上一行的行尾
+	for {
+		if lineEnd >= b.off && lineEnd <= b.off+int64(b.n) {
+			if i := bytes.LastIndexByte(b.buf[:lineEnd-b.off], '\n'); i >= 0 {
+				return b.line(b.off+int64(i)+1, lineEnd), false, nil
+			}
+			if b.off == 0 {
+				return b.line(0, lineEnd), false, nil
+			}
+			if lineEnd-b.off >= int64(len(b.buf)) {
+				// Buffer full?
+				// 缓冲区已满，说明行比缓冲区更长
+				break
+			}
+		}
+		if err := b.fill(lineEnd); err != nil {
+			return nil, false, err
+		}
+	}
+
+	lineStart, err := b.scanLong()
+	if err != nil {
+		return nil, false, err
+	}
+	b.long = true
+	b.next, b.stop = lineStart, lineEnd
+	b.end = lineStart
+	return b.readLong()
+}
+
+// line returns the buffered input bytes [start, end) and marks everything
+// from start on as returned.
+// line 返回缓冲区中输入的[start, end)字节，并将start之后的内容标记为已返回。
+func (b *ReverseReader) line(start, end int64) []byte {
+	b.end = start
+	return b.buf[start-b.off : end-b.off]
+}
+
+// byteAt returns the input byte at offset i.
+// byteAt 返回输入中偏移量为i的字节。
+func (b *ReverseReader) byteAt(i int64) (byte, error) {
+	if i < b.off || i >= b.off+int64(b.n) {
+		if err := b.fill(i + 1); err != nil {
+			return 0, err
+		}
+	}
+	return b.buf[i-b.off], nil
+}
+
+// fill loads as much of the input before end as fits into the buffer,
+// keeping the bytes that are already buffered.
+// fill 将end之前的输入尽可能多地加载到缓冲区中，并保留已经缓冲的字节。
+func (b *ReverseReader) fill(end int64) error {
+	start := end - int64(len(b.buf))
+	if start < 0 {
+		start = 0
+	}
+	size := int(end - start)
+	read := size
+	if b.n > 0 && b.off > start && b.off < end && b.off+int64(b.n) >= end {
+		// Slide the buffered bytes below end to the back of the
+		// buffer and read only what precedes them.
+		// 将end之前已缓冲的字节滑动到缓冲区的后部，只读取它们之前的部分
+		read = int(b.off - start)
+		copy(b.buf[read:size], b.buf[:end-b.off])
+	}
+	b.n = 0
+	if err := readFullAt(b.ra, b.buf[:read], start); err != nil {
+		return err
+	}
+	b.off = start
+	b.n = size
+	return nil
+}
+
+// scanLong searches backwards from the start of the buffer for the start
+// of a line that does not fit in the buffer.
+// scanLong 从缓冲区的开头向前查找一个放不进缓冲区的行的起始位置。
+func (b *ReverseReader) scanLong() (int64, error) {
+	for b.off > 0 {
+		if err := b.fill(b.off); err != nil {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(b.buf[:b.n], '\n'); i >= 0 {
+			return b.off + int64(i) + 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// readLong returns the next fragment of a line longer than the buffer.
+// readLong 返回比缓冲区更长的行的下一个片段。
+func (b *ReverseReader) readLong() (line []byte, isPrefix bool, err error) {
+	size := int64(len(b.buf))
+	if b.stop-b.next < size {
+		size = b.stop - b.next
+	}
+	b.n = 0
+	if err := readFullAt(b.ra, b.buf[:size], b.next); err != nil {
+		return nil, false, err
+	}
+	b.off = b.next
+	b.n = int(size)
+	b.next += size
+	b.long = b.next < b.stop
+	return b.buf[:size], b.long, nil
+}
+
+// readFullAt reads exactly len(p) bytes from ra at offset off.
+// A ReaderAt may return io.EOF together with a full read at the very end
+// of its input; only a short read is an error.
+// readFullAt 从ra的偏移量off处读取恰好len(p)个字节。
+// ReaderAt 在输入末尾读满时可能同时返回io.EOF；只有读取不足才算错误。
+func readFullAt(ra io.ReaderAt, p []byte, off int64) error {
+	n, err := ra.ReadAt(p, off)
+	if n < len(p) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+var reverseLineTests = []struct {
+	input string
+	want  []string // last line first
+}{
+	{"", nil},
+	{"\n", []string{""}},
+	{"\r\n", []string{""}},
+	{"\n\n", []string{"", ""}},
+	{"one", []string{"one"}},
+	{"one\n", []string{"one"}},
+	{"one\ntwo", []string{"two", "one"}},
+	{"one\r\ntwo\r\n", []string{"two", "one"}},
+	{"one\r\n\r\ntwo", []string{"two", "", "one"}},
+	{"bare\rcr\r\n", []string{"bare\rcr"}},
+	{"cr at end\r", []string{"cr at end\r"}},
+	{"mixed\nline\r\nends\n", []string{"ends", "line", "mixed"}},
+	{strings.Repeat("x", 40) + "\r\nshort\r\n", []string{"short", strings.Repeat("x", 40)}},
+	{"short\n" + strings.Repeat("y", 33) + "\r\n", []string{strings.Repeat("y", 33), "short"}},
+	{strings.Repeat("z", 16) + "\r\n" + strings.Repeat("w", 15) + "\r\n", []string{strings.Repeat("w", 15), strings.Repeat("z", 16)}},
+	{"a\n" + strings.Repeat("long line ", 10) + "\nb\n" + strings.Repeat("c", 50), []string{strings.Repeat("c", 50), "b", strings.Repeat("long line ", 10), "a"}},
+}
+
+// reverseLines reads all the lines of r, joining the fragments of long
+// lines.
+func reverseLines(r *ReverseReader) ([]string, error) {
+	var lines []string
+	var cur strings.Builder
+	for {
+		line, isPrefix, err := r.ReadLine()
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return lines, err
+		}
+		if line == nil {
+			return lines, io.ErrNoProgress
+		}
+		cur.Write(line)
+		if !isPrefix {
+			lines = append(lines, cur.String())
+			cur.Reset()
+		}
+	}
+}
+
+func TestReverseReader(t *testing.T) {
+	// Buffer size 16 is the minimum, and shorter than the long lines.
+	for _, bufSize := range []int{16, 4096} {
+		for _, tt := range reverseLineTests {
+			r := NewReverseReaderSize(strings.NewReader(tt.input), int64(len(tt.input)), bufSize)
+			got, err := reverseLines(r)
+			if err != nil || strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+				t.Errorf("%d/%q: lines = %q, %v, want %q", bufSize, tt.input, got, err, tt.want)
+			}
+		}
+	}
+}
+
+// TestReverseReaderForward checks the lines of each test input against
+// those returned by Reader.ReadLine.
+func TestReverseReaderForward(t *testing.T) {
+	for _, tt := range reverseLineTests {
+		r := NewReaderSize(strings.NewReader(tt.input), 4096)
+		var want []string
+		for {
+			line, _, err := r.ReadLine()
+			if err != nil {
+				break
+			}
+			want = append([]string{string(line)}, want...)
+		}
+		if strings.Join(want, "|") != strings.Join(tt.want, "|") || len(want) != len(tt.want) {
+			t.Errorf("%q: Reader.ReadLine gives %q, test wants %q", tt.input, want, tt.want)
+		}
+	}
+}
+
+func TestReverseReaderLongLineFragments(t *testing.T) {
+	long := strings.Repeat("0123456789", 4)
+	input := "first\r\n" + long + "\r\nlast\r\n"
+	r := NewReverseReaderSize(strings.NewReader(input), int64(len(input)), 16)
+	want := []struct {
+		line     string
+		isPrefix bool
+	}{
+		{"last", false},
+		{long[:16], true},
+		{long[16:32], true},
+		{long[32:], false},
+		{"first", false},
+	}
+	for _, w := range want {
+		line, isPrefix, err := r.ReadLine()
+		if string(line) != w.line || isPrefix != w.isPrefix || err != nil {
+			t.Fatalf("ReadLine = %q, %v, %v, want %q, %v, nil", line, isPrefix, err, w.line, w.isPrefix)
+		}
+	}
+	if line, _, err := r.ReadLine(); line != nil || err != io.EOF {
+		t.Fatalf("ReadLine at start = %q, %v, want nil, io.EOF", line, err)
+	}
+}
+
+func TestReverseReaderShortInput(t *testing.T) {
+	// The input is shorter than the size given to the ReverseReader.
+	r := NewReverseReaderSize(strings.NewReader("abc\n"), 10, 16)
+	if line, _, err := r.ReadLine(); line != nil || err != io.ErrUnexpectedEOF {
+		t.Fatalf("ReadLine = %q, %v, want nil, io.ErrUnexpectedEOF", line, err)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+var ErrNoSignature = errors.New("bufio: no signature matches")
+
+// SniffResult is the verdict of a sniffing predicate on the first bytes
+// of a stream.
+// SniffResult 是嗅探谓词对数据流前几个字节的判定结果。
+type SniffResult int
+
+const (
+	SniffNoMatch  SniffResult = iota // the stream does not match
+	SniffMatch                       // the stream matches
+	SniffNeedMore                    // more bytes are needed to decide
+)
+
+// A Sniffer recognizes the kind of a stream, such as the protocol spoken
+// on a connection, by matching registered signatures against the first
+// bytes of the stream. It only peeks at the stream and never consumes it,
+// so the Reader can be handed to the matching protocol handler afterwards.
+// Sniffer 通过将已注册的签名与数据流的前几个字节进行匹配来识别数据流的类型，例如连接上使用的协议。
+// 它只是窥视（peek）数据流而不会消费它，因此之后可以将Reader交给匹配的协议处理程序。
+//
+// Signatures are tried in the order they were registered, and the first
+// one that matches wins. A Sniffer must not be modified while Sniff is
+// running, but Sniff may be called from multiple goroutines.
+// 签名按照注册的顺序进行尝试，第一个匹配的签名胜出。Sniff运行期间不能修改Sniffer，但可以在多个goroutine中调用Sniff。
+type Sniffer struct {
+	rules []sniffRule
+}
+
+type sniffRule struct {
+	name   string
+	prefix []byte                     // for AddPrefix
+	match  func(p []byte) SniffResult // for AddFunc
+}
+
+// AddPrefix registers a signature named name that matches streams
+// starting with prefix.
+// AddPrefix 注册一个名为name的签名，它匹配以prefix开头的数据流。
+func (s *Sniffer) AddPrefix(name string, prefix []byte) {
+	s.rules = append(s.rules, sniffRule{name: name, prefix: bytes.Clone(prefix)})
+}
+
+// AddFunc registers a signature named name that is decided by match.
+// match is called with the bytes peeked so far and returns SniffNeedMore
+// if it cannot decide yet; it is then called again with more bytes.
+// AddFunc 注册一个名为name的签名，由match进行判定。match以目前已窥视到的字节为参数调用，
+// 如果还无法判定则返回SniffNeedMore；之后会用更多的字节再次调用它。
+func (s *Sniffer) AddFunc(name string, match func(p []byte) SniffResult) {
+	s.rules = append(s.rules, sniffRule{name: name, match: match})
+}
+
+// eval applies r to p and, if it needs more bytes, reports how many.
+// eval 将r应用于p，如果需要更多字节，则报告需要多少字节。
+func (r *sniffRule) eval(p []byte) (res SniffResult, want int) {
+	if r.match != nil {
+		res = r.match(p)
+		return res, len(p) + 1
+	}
+	if len(p) >= len(r.prefix) {
+		if bytes.HasPrefix(p, r.prefix) {
+			return SniffMatch, 0
+		}
+		return SniffNoMatch, 0
+	}
+	if bytes.HasPrefix(r.prefix, p) {
+		return SniffNeedMore, len(r.prefix)
+	}
+	return SniffNoMatch, 0
+}
+
+// Sniff returns the name of the first registered signature that matches
+// the stream read by b, without advancing b. It waits for only as many
+// bytes as are needed to decide: the data already buffered in b is tried
+// first, and b reads more only while an undecided signature precedes any
+// matching one.
+// Sniff 返回第一个与b读取的数据流匹配的已注册签名的名称，并且不会推进b。
+// 它只等待判定所需的字节：首先尝试b中已缓冲的数据，只有当某个尚未判定的签名排在任何已匹配的签名之前时，b才会读取更多数据。
+//
+// If no signature matches, Sniff returns ErrNoSignature; if the stream is
+// empty or reading from it fails, it returns the read error instead.
+// Signatures that would need more bytes than fit in b's buffer, or than
+// the stream holds, do not match. Any other read error, such as an expired
+// deadline, leaves them undecided, so Sniff returns it even if a later
+// signature matches.
+// 如果没有签名匹配，Sniff返回ErrNoSignature；如果数据流为空或者读取失败，则返回读取错误。
+// 需要的字节数超过b缓冲区大小或者超过数据流长度的签名不会匹配。其他任何读取错误（例如超时时间到期）都会使这些签名无法判定，
+// 因此即使后面的某个签名匹配，Sniff也会返回该错误。
+//
+// Calling Sniff prevents a UnreadByte or UnreadRune call from succeeding
+// until the next read operation.
+// 调用 Sniff 会阻止 UnreadByte 或 UnreadRune 调用成功，直到下一次读取操作。
+func (s *Sniffer) Sniff(b *Reader) (string, error) {
+	n := b.Buffered()
+	for {
+		p, err := b.Peek(n)
+		want := 0
+		for i := range s.rules {
+			res, w := s.rules[i].eval(p)
+			if res == SniffNeedMore && err != nil {
+				if err != io.EOF && err != ErrBufferFull {
+					// The bytes may still come.
+					// 这些字节可能之后仍会到来
+					return "", err
+				}
+				// No more bytes will come.
+				// 不会再有更多字节了
+				res = SniffNoMatch
+			}
+			if res == SniffMatch {
+				return s.rules[i].name, nil
+			}
+			if res == SniffNeedMore {
+				// An earlier signature takes precedence,
+				// so it must be decided first.
+				// 排在前面的签名优先，因此必须先判定它
+				want = w
+				break
+			}
+		}
+		if want == 0 {
+			if err != nil && err != ErrBufferFull && (len(p) == 0 || err != io.EOF) {
+				return "", err
+			}
+			return "", ErrNoSignature
+		}
+		// Try all the buffered data at once rather than one more
+		// byte at a time.
+		// 一次尝试所有已缓冲的数据，而不是每次只多尝试一个字节
+		n = max(want, b.Buffered())
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"bufio/bufiotest"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func newTestSniffer() *Sniffer {
+	var s Sniffer
+	s.AddPrefix("h2", []byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"))
+	s.AddPrefix("tls", []byte{0x16, 0x03})
+	// An HTTP/1 request starts with an upper-case method and a space.
+	s.AddFunc("http1", func(p []byte) SniffResult {
+		i := strings.IndexByte(string(p), ' ')
+		if i < 0 {
+			if len(p) > 8 {
+				return SniffNoMatch
+			}
+			return SniffNeedMore
+		}
+		if i > 0 && strings.ToUpper(string(p[:i])) == string(p[:i]) {
+			return SniffMatch
+		}
+		return SniffNoMatch
+	})
+	return &s
+}
+
+var sniffTests = []struct {
+	name      string
+	input     string
+	failAfter int64 // inject a deadline error after this many bytes
+	want      string
+	err       error
+}{
+	{"h2", "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\nframes", 0, "h2", nil},
+	{"http1", "GET / HTTP/1.1\r\n", 0, "http1", nil},
+	{"http1 sharing a prefix with h2", "PRIX / HTTP/1.1\r\n", 0, "http1", nil},
+	{"tls", "\x16\x03\x01", 0, "tls", nil},
+	{"no match", "garbage-here", 0, "", ErrNoSignature},
+	{"undecided at EOF", "PRI", 0, "", ErrNoSignature},
+	{"empty", "", 0, "", io.EOF},
+	{"deadline with undecided signature", "\x16\x03\x01", 1, "", os.ErrDeadlineExceeded},
+	{"deadline before precedence is decided", "PRI * HTTP/1.1", 5, "", os.ErrDeadlineExceeded},
+	{"deadline after match", "\x16\x03\x01", 2, "tls", nil},
+	{"deadline before any data", "GET /", -1, "", os.ErrDeadlineExceeded},
+}
+
+func TestSniff(t *testing.T) {
+	s := newTestSniffer()
+	for _, tt := range sniffTests {
+		src := &bufiotest.Reader{R: strings.NewReader(tt.input), Chunks: []int{1}}
+		if tt.failAfter != 0 {
+			src.FailAfter = max(tt.failAfter, 0)
+			src.Err = os.ErrDeadlineExceeded
+		}
+		b := NewReaderSize(src, 32)
+		got, err := s.Sniff(b)
+		if got != tt.want || err != tt.err {
+			t.Errorf("%s: Sniff = %q, %v, want %q, %v", tt.name, got, err, tt.want, tt.err)
+		}
+		// Sniff does not consume the stream.
+		src.Err = nil
+		rest, _ := io.ReadAll(b)
+		if string(rest) != tt.input {
+			t.Errorf("%s: stream after Sniff = %q, want %q", tt.name, rest, tt.input)
+		}
+	}
+}
+
+// TestSniffAfterTimeout checks that a Sniff that failed because a deadline
+// expired can be retried.
+func TestSniffAfterTimeout(t *testing.T) {
+	src := &bufiotest.Reader{R: strings.NewReader("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"), FailAfter: 4, Err: os.ErrDeadlineExceeded}
+	b := NewReader(src)
+	s := newTestSniffer()
+	if _, err := s.Sniff(b); err != os.ErrDeadlineExceeded {
+		t.Fatalf("Sniff = %v, want %v", err, os.ErrDeadlineExceeded)
+	}
+	src.Err = nil
+	if got, err := s.Sniff(b); got != "h2" || err != nil {
+		t.Fatalf("Sniff after timeout = %q, %v, want %q, nil", got, err, "h2")
+	}
+}
+
+func TestSniffBufferFull(t *testing.T) {
+	var s Sniffer
+	s.AddPrefix("long", []byte(strings.Repeat("x", 20)))
+	s.AddPrefix("short", []byte("x"))
+	b := NewReaderSize(strings.NewReader(strings.Repeat("x", 40)), 16)
+	if got, err := s.Sniff(b); got != "short" || err != nil {
+		t.Fatalf("Sniff = %q, %v, want %q, nil", got, err, "short")
+	}
+}
+
+// TestSniffBuffered checks that a signature that needs more bytes is
+// given all those already buffered, instead of one more at a time.
+func TestSniffBuffered(t *testing.T) {
+	var s Sniffer
+	calls := 0
+	s.AddFunc("line", func(p []byte) SniffResult {
+		calls++
+		if strings.IndexByte(string(p), '\n') < 0 {
+			return SniffNeedMore
+		}
+		return SniffMatch
+	})
+	b := NewReaderSize(strings.NewReader(strings.Repeat("x", 1000)+"\n"), 2048)
+	if got, err := s.Sniff(b); got != "line" || err != nil {
+		t.Fatalf("Sniff = %q, %v, want %q, nil", got, err, "line")
+	}
+	if calls > 3 {
+		t.Errorf("signature called %d times, want at most 3", calls)
+	}
+}
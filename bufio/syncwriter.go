@@ -0,0 +1,146 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+import (
+	"io"
+	"sync"
+)
+
+// WriteSyncer is an io.Writer that can commit the data written to it to
+// stable storage, such as an *os.File.
+// WriteSyncer 是一个能够将写入的数据提交到稳定存储的io.Writer，例如*os.File。
+type WriteSyncer interface {
+	io.Writer
+	Sync() error
+}
+
+// SyncWriter is a Writer for durable output, such as a write-ahead log,
+// that may be shared by several goroutines. In addition to Flush, which
+// only hands the buffered data to the underlying writer, it offers
+// FlushSync, which returns once the data is on stable storage.
+// SyncWriter 是一个用于持久化输出（例如预写日志）的Writer，可以被多个goroutine共享。
+// 除了只把缓冲数据交给底层写入器的Flush之外，它还提供FlushSync，在数据到达稳定存储后才返回。
+//
+// FlushSync performs group commit: while one caller waits for Sync, the
+// data written by others keeps accumulating in the buffer, and the
+// callers that arrive in the meantime are served together by a single
+// write and Sync once it is done.
+// FlushSync 实现了组提交：当一个调用者等待Sync时，其他goroutine写入的数据继续在缓冲区中积累，
+// 这期间到达的调用者在Sync完成后由一次写入和一次Sync共同处理。
+type SyncWriter struct {
+	mu   sync.Mutex
+	cond sync.Cond // signaled when a Sync completes
+
+	wr      *Writer
+	sy      WriteSyncer
+	written int64 // bytes accepted by Write
+	durable int64 // bytes known to be on stable storage
+	syncing bool  // a FlushSync caller is waiting for Sync
+	err     error // sticky; the durability of the data after durable is unknown
+}
+
+// NewSyncWriterSize returns a new SyncWriter writing to w whose buffer
+// has at least the specified size.
+// NewSyncWriterSize 返回一个写入w的新SyncWriter，其缓冲区至少具有指定的大小。
+func NewSyncWriterSize(w WriteSyncer, size int) *SyncWriter {
+	s := &SyncWriter{
+		wr: NewWriterSize(w, size),
+		sy: w,
+	}
+	s.cond.L = &s.mu
+	return s
+}
+
+// NewSyncWriter returns a new SyncWriter writing to w whose buffer has
+// the default size.
+// NewSyncWriter 返回一个写入w的新SyncWriter，其缓冲区具有默认大小。
+func NewSyncWriter(w WriteSyncer) *SyncWriter {
+	return NewSyncWriterSize(w, defaultBufSize)
+}
+
+// Write writes the contents of p into the buffer; see Writer.Write.
+// The data of a single call is never split by a concurrent FlushSync.
+// Write 将p的内容写入缓冲区；参见Writer.Write。单次调用的数据不会被并发的FlushSync拆分。
+func (s *SyncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	n, err := s.wr.Write(p)
+	s.written += int64(n)
+	return n, err
+}
+
+// WriteString writes a string into the buffer; see Writer.WriteString.
+// WriteString 将字符串写入缓冲区；参见Writer.WriteString。
+func (s *SyncWriter) WriteString(str string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	n, err := s.wr.WriteString(str)
+	s.written += int64(n)
+	return n, err
+}
+
+// Flush writes any buffered data to the underlying writer without
+// waiting for it to reach stable storage.
+// Flush 将任何缓冲数据写入底层写入器，但不等待数据到达稳定存储。
+func (s *SyncWriter) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	return s.wr.Flush()
+}
+
+// FlushSync writes the buffered data to the underlying writer and commits
+// it to stable storage. It returns nil once everything written before the
+// call is durable. If the write or Sync fails, FlushSync returns the error
+// to every caller whose data is not known to be durable, and since it is
+// then unknown what reached storage, the SyncWriter fails all later calls
+// with the same error.
+// FlushSync 将缓冲数据写入底层写入器并提交到稳定存储。当调用之前写入的所有数据都已持久化时返回nil。
+// 如果写入或Sync失败，FlushSync会向所有数据未确认持久化的调用者返回该错误；由于此时无法知道哪些数据已经到达存储，
+// SyncWriter之后的所有调用都会以同样的错误失败。
+func (s *SyncWriter) FlushSync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	target := s.written
+	for s.durable < target {
+		if s.err != nil {
+			return s.err
+		}
+		if s.syncing {
+			// Another caller's Sync is in progress; the data after
+			// it goes with the next one.
+			// 另一个调用者的Sync正在进行；之后的数据由下一次Sync处理
+			s.cond.Wait()
+			continue
+		}
+		end := s.written
+		if err := s.wr.Flush(); err != nil {
+			s.err = err
+			s.cond.Broadcast()
+			return err
+		}
+		s.syncing = true
+		s.mu.Unlock()
+		err := s.sy.Sync()
+		s.mu.Lock()
+		s.syncing = false
+		if err != nil {
+			s.err = err
+		} else {
+			s.durable = end
+		}
+		s.cond.Broadcast()
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"bufio/bufiotest"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var errSync = errors.New("sync failed")
+
+// testSyncer records what has been synced. If release is not nil, Sync
+// reports on entered and waits for release to be closed.
+type testSyncer struct {
+	w       io.Writer
+	out     strings.Builder
+	durable string
+	syncs   int
+	err     error
+
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (s *testSyncer) Write(p []byte) (int, error) {
+	if s.w != nil {
+		return s.w.Write(p)
+	}
+	return s.out.Write(p)
+}
+
+func (s *testSyncer) Sync() error {
+	s.syncs++
+	if s.release != nil {
+		select {
+		case s.entered <- struct{}{}:
+		default:
+		}
+		<-s.release
+	}
+	if s.err != nil {
+		return s.err
+	}
+	s.durable = s.out.String()
+	return nil
+}
+
+var syncWriterErrorTests = []struct {
+	name  string
+	sy    func() *testSyncer
+	err   error
+	syncs int
+}{
+	{
+		"Sync fails",
+		func() *testSyncer { return &testSyncer{err: errSync} },
+		errSync,
+		1,
+	},
+	{
+		"write fails",
+		func() *testSyncer {
+			s := new(testSyncer)
+			s.w = &bufiotest.Writer{W: &s.out, FailAfter: 2, Err: bufiotest.ErrInjected}
+			return s
+		},
+		bufiotest.ErrInjected,
+		0,
+	},
+}
+
+func TestSyncWriterError(t *testing.T) {
+	for _, tt := range syncWriterErrorTests {
+		sy := tt.sy()
+		w := NewSyncWriterSize(sy, 16)
+		w.WriteString("abc")
+		if err := w.FlushSync(); err != tt.err {
+			t.Errorf("%s: FlushSync = %v, want %v", tt.name, err, tt.err)
+		}
+		// The error is sticky, and nothing is retried.
+		if n, err := w.Write([]byte("x")); n != 0 || err != tt.err {
+			t.Errorf("%s: Write after error = %d, %v", tt.name, n, err)
+		}
+		if n, err := w.WriteString("x"); n != 0 || err != tt.err {
+			t.Errorf("%s: WriteString after error = %d, %v", tt.name, n, err)
+		}
+		if err := w.Flush(); err != tt.err {
+			t.Errorf("%s: Flush after error = %v", tt.name, err)
+		}
+		if err := w.FlushSync(); err != tt.err {
+			t.Errorf("%s: FlushSync after error = %v", tt.name, err)
+		}
+		if sy.syncs != tt.syncs || sy.durable != "" {
+			t.Errorf("%s: %d syncs with %q durable, want %d syncs and nothing durable", tt.name, sy.syncs, sy.durable, tt.syncs)
+		}
+	}
+}
+
+// TestSyncWriterGroupCommit checks that the callers arriving during a Sync
+// are served together by the next one.
+func TestSyncWriterGroupCommit(t *testing.T) {
+	sy := &testSyncer{entered: make(chan struct{}, 1), release: make(chan struct{})}
+	w := NewSyncWriterSize(sy, 16)
+	w.WriteString("first\n")
+	first := make(chan error)
+	go func() { first <- w.FlushSync() }()
+	<-sy.entered
+
+	// Written while the first Sync is in progress.
+	const n = 8
+	for i := range n {
+		w.WriteString(string(rune('a'+i)) + "\n")
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = w.FlushSync()
+		}()
+	}
+	close(sy.release)
+	if err := <-first; err != nil {
+		t.Fatalf("first FlushSync = %v", err)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("FlushSync %d = %v", i, err)
+		}
+	}
+	if want := "first\na\nb\nc\nd\ne\nf\ng\nh\n"; sy.durable != want {
+		t.Errorf("durable = %q, want %q", sy.durable, want)
+	}
+	if sy.syncs != 2 {
+		t.Errorf("%d syncs, want 2", sy.syncs)
+	}
+}
+
+// TestSyncWriterGroupError checks that a failed Sync fails the callers
+// waiting for it as well as the one that started it.
+func TestSyncWriterGroupError(t *testing.T) {
+	sy := &testSyncer{err: errSync, entered: make(chan struct{}, 1), release: make(chan struct{})}
+	w := NewSyncWriterSize(sy, 16)
+	w.WriteString("first\n")
+	first := make(chan error)
+	go func() { first <- w.FlushSync() }()
+	<-sy.entered
+	w.WriteString("second\n")
+	second := make(chan error)
+	go func() { second <- w.FlushSync() }()
+	close(sy.release)
+	if err := <-first; err != errSync {
+		t.Errorf("first FlushSync = %v, want %v", err, errSync)
+	}
+	if err := <-second; err != errSync {
+		t.Errorf("waiting FlushSync = %v, want %v", err, errSync)
+	}
+	if sy.syncs != 1 {
+		t.Errorf("%d syncs, want 1", sy.syncs)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import (
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+
+	"internal/race"
+)
+
+// An AdaptiveMutex is a mutual exclusion lock that learns how long it is
+// usually held, and picks how to wait for it accordingly. A Mutex spins
+// for a few iterations before parking whatever the holder is doing; an
+// AdaptiveMutex keeps spinning while the lock is expected to be released
+// sooner than parking and waking a goroutine would take, yields the
+// processor while it is expected within a few scheduling rounds, and parks
+// at once when it is held for long.
+// AdaptiveMutex 是一个会学习自己通常被持有多久，并据此选择等待方式的互斥锁。无论持有者在做什么，
+// Mutex都在自旋几次之后挂起；而AdaptiveMutex在预计锁的释放早于挂起并唤醒一个goroutine所需的时间时持续自旋，
+// 在预计几轮调度之内会释放时让出处理器，在锁被长时间持有时则立即挂起。
+//
+// The hold times are measured by a clock reading in every Lock and Unlock,
+// which makes an uncontended AdaptiveMutex somewhat slower than a Mutex.
+// Once waiting has been decided on, it parks like a Mutex, including the
+// switch to starvation mode. The zero value is an unlocked mutex.
+// 持有时间通过在每次Lock和Unlock中读取一次时钟来测量，这使得没有竞争的AdaptiveMutex比Mutex稍慢。
+// 一旦决定等待，它就像Mutex一样挂起，包括切换到饥饿模式。零值是一个未锁定的互斥锁。
+//
+// An AdaptiveMutex must not be copied after first use.
+// AdaptiveMutex 在第一次使用后不能被复制。
+type AdaptiveMutex struct {
+	mu       Mutex
+	lockedAt int64        // runtime_nanotime when the current holder acquired mu
+	hold     atomic.Int64 // moving average of the hold time, in nanoseconds
+}
+
+const (
+	// Expected waits up to adaptiveSpinNs are spent spinning, which is
+	// about the cost of parking a goroutine and waking it up again, and
+	// waits up to adaptiveYieldNs are spent yielding.
+	// 预计不超过adaptiveSpinNs的等待用于自旋，这大约是挂起一个goroutine再唤醒它的代价；
+	// 预计不超过adaptiveYieldNs的等待用于让出处理器。
+	adaptiveSpinNs  = 4e3  // 4µs
+	adaptiveYieldNs = 50e3 // 50µs
+
+	// The moving average gives each new hold time a weight of
+	// 1/(1<<adaptiveHoldShift).
+	// 移动平均给予每个新的持有时间1/(1<<adaptiveHoldShift)的权重。
+	adaptiveHoldShift = 3
+)
+
+// Lock locks m. If the lock is already in use, the calling goroutine
+// spins, yields or blocks until the mutex is available, depending on how
+// long m has been held recently.
+// Lock 锁定m。如果锁已经被使用，调用者goroutine会根据m最近被持有的时长，自旋、让出处理器或者阻塞，直到mutex可用。
+func (m *AdaptiveMutex) Lock() {
+	if debugOwners {
+		defer m.mu.debugLocked(m.mu.debugLocking())
+	}
+	if lockdepEnabled {
+		defer lockdepLocked(lockdepLocking(lockKey{p: unsafe.Pointer(&m.mu)}))
+	}
+	if !m.tryAcquire() && !m.lockAdaptive() {
+		m.mu.lockSlow(nil, starvationThresholdNs)
+	}
+	m.lockedAt = runtime_nanotime()
+}
+
+// tryAcquire acquires mu if it is free and not reserved for a starving
+// waiter.
+// tryAcquire 在mu空闲且没有为饥饿的等待者保留时获取它。
+func (m *AdaptiveMutex) tryAcquire() bool {
+	old := atomic.LoadInt32(&m.mu.state)
+	if old&(mutexLocked|mutexStarving) != 0 || !atomic.CompareAndSwapInt32(&m.mu.state, old, old|mutexLocked) {
+		return false
+	}
+	if race.Enabled {
+		race.Acquire(unsafe.Pointer(&m.mu))
+	}
+	return true
+}
+
+// lockAdaptive spins for up to adaptiveSpinNs if m is usually held for
+// less than that, or yields for up to twice the average hold time if it is
+// held for less than adaptiveYieldNs. It reports whether it acquired the
+// lock; if not, the caller parks.
+// lockAdaptive 如果m通常被持有的时间少于adaptiveSpinNs，则最多自旋adaptiveSpinNs；如果少于adaptiveYieldNs，
+// 则最多在平均持有时间的两倍之内让出处理器。它报告是否获取到了锁；如果没有，调用者挂起。
+func (m *AdaptiveMutex) lockAdaptive() bool {
+	hold := m.hold.Load()
+	if hold > adaptiveYieldNs {
+		return false
+	}
+	spin := hold <= adaptiveSpinNs
+	budget := 2 * hold
+	if spin {
+		budget = adaptiveSpinNs
+	}
+	deadline := runtime_nanotime() + budget
+	for {
+		if spin && runtime_canSpin(0) {
+			runtime_doSpin()
+		} else {
+			runtime.Gosched()
+		}
+		if m.tryAcquire() {
+			return true
+		}
+		if runtime_nanotime() > deadline {
+			return false
+		}
+	}
+}
+
+// TryLock tries to lock m and reports whether it succeeded; see
+// Mutex.TryLock.
+// TryLock 尝试锁定m并报告是否成功；参见Mutex.TryLock。
+func (m *AdaptiveMutex) TryLock() bool {
+	if !m.mu.TryLock() {
+		return false
+	}
+	m.lockedAt = runtime_nanotime()
+	return true
+}
+
+// Unlock unlocks m. It is a run-time error if m is not locked on entry to
+// Unlock.
+// Unlock 解锁m。如果m在进入Unlock时没有被锁定，则会产生运行时错误。
+func (m *AdaptiveMutex) Unlock() {
+	// Only the holder updates the average, so a load and a store
+	// suffice; waiters may read it at any time.
+	// 只有持有者更新平均值，因此一次读取和一次存储就足够了；等待者随时可能读取它
+	if m.lockedAt != 0 {
+		hold := m.hold.Load()
+		m.hold.Store(hold + (runtime_nanotime()-m.lockedAt-hold)>>adaptiveHoldShift)
+		m.lockedAt = 0
+	}
+	m.mu.Unlock()
+}
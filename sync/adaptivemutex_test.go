@@ -0,0 +1,116 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync_test
+
+import (
+	. "sync"
+	"testing"
+)
+
+func TestAdaptiveMutex(t *testing.T) {
+	var m AdaptiveMutex
+	if !m.TryLock() {
+		t.Fatal("TryLock of unlocked AdaptiveMutex failed")
+	}
+	if m.TryLock() {
+		t.Fatal("TryLock of locked AdaptiveMutex succeeded")
+	}
+	m.Unlock()
+
+	// Mix short and long critical sections, so that waiters go through
+	// the spinning, yielding and parking paths as the average changes.
+	const n, iters = 8, 1000
+	count := 0
+	done := make(chan bool)
+	for i := 0; i < n; i++ {
+		go func() {
+			for j := 0; j < iters; j++ {
+				m.Lock()
+				count++
+				if j%100 == 0 {
+					delay(10000)
+				}
+				m.Unlock()
+			}
+			done <- true
+		}()
+	}
+	for i := 0; i < n; i++ {
+		<-done
+	}
+	if count != n*iters {
+		t.Fatalf("count = %d, want %d", count, n*iters)
+	}
+}
+
+var delaySink int
+
+// delay does about n units of work that the compiler cannot remove.
+func delay(n int) {
+	v := delaySink
+	for i := 0; i < n; i++ {
+		v *= 2
+		v /= 2
+	}
+	delaySink = v
+}
+
+// Contention levels for the benchmarks below: the work done inside and
+// outside the critical section, and the number of goroutines per P.
+var contentionLevels = []struct {
+	name            string
+	inside, outside int
+	parallelism     int
+}{
+	{"low", 10, 1000, 1},
+	{"medium", 100, 100, 1},
+	{"high", 1000, 10, 4},
+}
+
+func benchmarkContention(b *testing.B, newLocker func() Locker) {
+	for _, c := range contentionLevels {
+		b.Run(c.name, func(b *testing.B) {
+			l := newLocker()
+			b.SetParallelism(c.parallelism)
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					l.Lock()
+					delay(c.inside)
+					l.Unlock()
+					delay(c.outside)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkContentionMutex(b *testing.B) {
+	benchmarkContention(b, func() Locker { return new(Mutex) })
+}
+
+func BenchmarkContentionAdaptiveMutex(b *testing.B) {
+	benchmarkContention(b, func() Locker { return new(AdaptiveMutex) })
+}
+
+// The long variants hold the lock for much longer than it takes to park
+// a goroutine, where spinning only wastes CPU that the holder could use.
+func benchmarkLongHold(b *testing.B, l Locker) {
+	b.SetParallelism(2)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Lock()
+			delay(100000)
+			l.Unlock()
+		}
+	})
+}
+
+func BenchmarkLongHoldMutex(b *testing.B) {
+	benchmarkLongHold(b, new(Mutex))
+}
+
+func BenchmarkLongHoldAdaptiveMutex(b *testing.B) {
+	benchmarkLongHold(b, new(AdaptiveMutex))
+}
@@ -0,0 +1,12 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !syncdebug
+
+package sync
+
+// debugOwners reports whether the owners of locks are tracked; see
+// owner.go. Build with -tags syncdebug to enable it.
+// debugOwners 报告是否跟踪锁的持有者；参见owner.go。使用-tags syncdebug构建以启用它。
+const debugOwners = false
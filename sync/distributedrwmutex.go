@@ -0,0 +1,282 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import (
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+
+	"internal/race"
+)
+
+// A DistributedRWMutex is a reader/writer mutual exclusion lock for data
+// that is read on many processors at once and rarely written. An RWMutex
+// counts its readers in a single word, which every RLock and RUnlock
+// updates, so that readers on different processors contend for its cache
+// line. A DistributedRWMutex keeps one reader count per P instead, each
+// on a cache line of its own, and readers only update the count of the P
+// they run on; in exchange, a writer has to sweep all the counts.
+// DistributedRWMutex 是一个读写互斥锁，适用于同时在许多处理器上读取而很少写入的数据。RWMutex用一个字来统计读者，
+// 每次RLock和RUnlock都要更新它，因此不同处理器上的读者会争用它所在的缓存行。DistributedRWMutex则为每个P保存一个读者计数，
+// 每个计数独占一个缓存行，读者只更新其所运行的P的计数；作为交换，写者必须扫描所有的计数。
+//
+// Writers are preferred: once a writer calls Lock, new readers wait until
+// it unlocks, and a stream of writers can keep readers waiting. The
+// counts take 128 bytes per P, allocated on first use.
+// 写者优先：一旦有写者调用Lock，新的读者就要等到它解锁，源源不断的写者可能让读者一直等待。
+// 计数为每个P占用128字节，在第一次使用时分配。
+//
+// The zero value for a DistributedRWMutex is an unlocked mutex. As with
+// RWMutex, recursive read locking is prohibited, and a DistributedRWMutex
+// must not be copied after first use.
+// DistributedRWMutex 的零值是一个未锁定的互斥锁。和RWMutex一样，禁止递归读锁定，
+// 并且DistributedRWMutex在第一次使用后不能被复制。
+type DistributedRWMutex struct {
+	w       Mutex                       // held by the writer
+	writer  atomic.Bool                 // a writer holds or waits for the lock
+	shards  atomic.Pointer[[]readShard] // per-P reader counts
+	readerQ notifyList                  // readers waiting for the writer to unlock
+	writerQ notifyList                  // the writer waiting for readers to unlock
+}
+
+// readShard is the reader count of a P. A reader may unlock on another P
+// than the one it locked on, so a single count can be negative; only the
+// sum over all shards is the number of readers.
+// readShard 是一个P的读者计数。读者解锁时所在的P可能与加锁时不同，因此单个计数可能为负数；
+// 只有所有分片的总和才是读者的数量。
+type readShard struct {
+	readers atomic.Int64
+
+	// Prevents false sharing on widespread platforms with
+	// 128 mod (cache line size) = 0 .
+	// 防止在广泛使用的平台上出现128 mod (cache line size) = 0的错误共享。
+	pad [128 - unsafe.Sizeof(atomic.Int64{})%128]byte
+}
+
+// A reader increments its shard and then checks writer, while a writer
+// sets writer and then sums the shards. All the operations are
+// sequentially consistent, so at least one of the two sees the other: the
+// writer counts the reader, or the reader sees the writer and backs out.
+// A goroutine that has to wait takes a ticket on its notifyList before
+// checking again, so a notification sent after the check cannot be
+// missed; tickets that are not waited for need no notification.
+//
+// Happens-before relationships are indicated to the race detector as for
+// RWMutex, with readerQ and writerQ in place of readerSem and writerSem,
+// and race synchronization is disabled in between, so that the shared
+// counts do not appear to synchronize the readers with each other.
+//
+// 读者先递增自己的分片再检查writer，而写者先设置writer再对各分片求和。所有操作都是顺序一致的，
+// 因此两者至少有一方能看到另一方：要么写者计入了读者，要么读者看到了写者并退出。
+// 需要等待的goroutine在再次检查之前先在自己的notifyList上领取一张票，因此检查之后发出的通知不会丢失；
+// 没有被等待的票不需要通知。
+//
+// happens-before关系以与RWMutex相同的方式指示给race检测器，用readerQ和writerQ代替readerSem和writerSem，
+// 其间禁用race同步，这样共享的计数不会看起来在读者之间建立了同步。
+
+// shard returns the reader count of the caller's P.
+// shard 返回调用者所在P的读者计数。
+func (rw *DistributedRWMutex) shard() *readShard {
+	shards := rw.shards.Load()
+	if shards == nil {
+		shards = rw.allocShards()
+	}
+	// The caller may move to another P once unpinned, which only costs
+	// some contention.
+	// 解除固定之后调用者可能移到另一个P上，这只会带来一些争用
+	pid := runtime_procPin()
+	runtime_procUnpin()
+	return &(*shards)[pid%len(*shards)]
+}
+
+func (rw *DistributedRWMutex) allocShards() *[]readShard {
+	shards := make([]readShard, runtime.GOMAXPROCS(0))
+	if !rw.shards.CompareAndSwap(nil, &shards) {
+		return rw.shards.Load()
+	}
+	return &shards
+}
+
+// RLock locks rw for reading. It blocks while a writer holds or waits for
+// rw.
+// RLock 为读锁定rw。当有写者持有或等待rw时它会阻塞。
+func (rw *DistributedRWMutex) RLock() {
+	if lockdepEnabled {
+		defer lockdepLocked(lockdepLocking(lockKey{p: unsafe.Pointer(rw), rw: true}))
+	}
+	if race.Enabled {
+		race.Disable()
+	}
+	s := rw.shard()
+	for {
+		s.readers.Add(1)
+		if !rw.writer.Load() {
+			break
+		}
+		// A writer is pending; let it go first.
+		// 有写者在等待；让它先执行
+		s.readers.Add(-1)
+		runtime_notifyListNotifyAll(&rw.writerQ)
+		t := runtime_notifyListAdd(&rw.readerQ)
+		if rw.writer.Load() {
+			runtime_notifyListWait(&rw.readerQ, t)
+		}
+	}
+	if race.Enabled {
+		race.Enable()
+		race.Acquire(unsafe.Pointer(&rw.readerQ))
+	}
+}
+
+// TryRLock tries to lock rw for reading and reports whether it succeeded.
+// TryRLock 尝试为读锁定rw，并报告是否成功。
+func (rw *DistributedRWMutex) TryRLock() bool {
+	if race.Enabled {
+		race.Disable()
+	}
+	s := rw.shard()
+	s.readers.Add(1)
+	if rw.writer.Load() {
+		s.readers.Add(-1)
+		runtime_notifyListNotifyAll(&rw.writerQ)
+		if race.Enabled {
+			race.Enable()
+		}
+		return false
+	}
+	if race.Enabled {
+		race.Enable()
+		race.Acquire(unsafe.Pointer(&rw.readerQ))
+	}
+	if lockdepEnabled {
+		lockdepLocked(lockdepSite(lockKey{p: unsafe.Pointer(rw), rw: true}))
+	}
+	return true
+}
+
+// RUnlock undoes a single RLock call. The caller must hold rw for reading;
+// unlike RWMutex, a DistributedRWMutex cannot detect an RUnlock without a
+// matching RLock.
+// RUnlock 撤销一次RLock调用。调用者必须为读持有rw；与RWMutex不同，DistributedRWMutex无法检测出没有对应RLock的RUnlock。
+func (rw *DistributedRWMutex) RUnlock() {
+	if lockdepEnabled {
+		lockdepRelease(lockKey{p: unsafe.Pointer(rw), rw: true})
+	}
+	if race.Enabled {
+		race.ReleaseMerge(unsafe.Pointer(&rw.writerQ))
+		race.Disable()
+	}
+	rw.shard().readers.Add(-1)
+	if rw.writer.Load() {
+		// The writer may be waiting for this reader.
+		// 写者可能正在等待这个读者
+		runtime_notifyListNotifyAll(&rw.writerQ)
+	}
+	if race.Enabled {
+		race.Enable()
+	}
+}
+
+// readers returns the number of goroutines holding rw for reading, or
+// trying to.
+// readers 返回为读持有rw或者正在尝试为读持有rw的goroutine的数量。
+func (rw *DistributedRWMutex) readers() int64 {
+	shards := rw.shards.Load()
+	if shards == nil {
+		return 0
+	}
+	var n int64
+	for i := range *shards {
+		n += (*shards)[i].readers.Load()
+	}
+	return n
+}
+
+// Lock locks rw for writing. It blocks new readers, and waits for the
+// current ones and for any other writer to unlock.
+// Lock 为写锁定rw。它会阻止新的读者，并等待当前的读者以及其他写者解锁。
+func (rw *DistributedRWMutex) Lock() {
+	if lockdepEnabled {
+		defer lockdepLocked(lockdepLocking(lockKey{p: unsafe.Pointer(rw), rw: true}))
+	}
+	if race.Enabled {
+		race.Disable()
+	}
+	// First, resolve competition with other writers.
+	// 首先，解决与其他写者的竞争
+	rw.w.Lock()
+	rw.writer.Store(true)
+	for {
+		t := runtime_notifyListAdd(&rw.writerQ)
+		if rw.readers() == 0 {
+			break
+		}
+		runtime_notifyListWait(&rw.writerQ, t)
+	}
+	if race.Enabled {
+		race.Enable()
+		race.Acquire(unsafe.Pointer(&rw.readerQ))
+		race.Acquire(unsafe.Pointer(&rw.writerQ))
+	}
+}
+
+// TryLock tries to lock rw for writing and reports whether it succeeded.
+// TryLock 尝试为写锁定rw，并报告是否成功。
+func (rw *DistributedRWMutex) TryLock() bool {
+	if race.Enabled {
+		race.Disable()
+	}
+	if !rw.w.TryLock() {
+		if race.Enabled {
+			race.Enable()
+		}
+		return false
+	}
+	rw.writer.Store(true)
+	if rw.readers() != 0 {
+		rw.writer.Store(false)
+		runtime_notifyListNotifyAll(&rw.readerQ)
+		rw.w.Unlock()
+		if race.Enabled {
+			race.Enable()
+		}
+		return false
+	}
+	if race.Enabled {
+		race.Enable()
+		race.Acquire(unsafe.Pointer(&rw.readerQ))
+		race.Acquire(unsafe.Pointer(&rw.writerQ))
+	}
+	if lockdepEnabled {
+		lockdepLocked(lockdepSite(lockKey{p: unsafe.Pointer(rw), rw: true}))
+	}
+	return true
+}
+
+// Unlock unlocks rw for writing. It is a run-time error if rw is not
+// locked for writing on entry to Unlock.
+// Unlock 解除rw的写锁定。如果rw在进入Unlock时没有为写锁定，则会产生运行时错误。
+func (rw *DistributedRWMutex) Unlock() {
+	if lockdepEnabled {
+		lockdepRelease(lockKey{p: unsafe.Pointer(rw), rw: true})
+	}
+	if race.Enabled {
+		race.Release(unsafe.Pointer(&rw.readerQ))
+		race.Disable()
+	}
+	if !rw.writer.Load() {
+		race.Enable()
+		fatal("sync: Unlock of unlocked DistributedRWMutex")
+	}
+	rw.writer.Store(false)
+	runtime_notifyListNotifyAll(&rw.readerQ)
+	// Allow other writers to proceed.
+	// 允许其他写者继续竞争锁
+	rw.w.Unlock()
+	if race.Enabled {
+		race.Enable()
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync_test
+
+import (
+	"runtime"
+	. "sync"
+	"sync/atomic"
+	"testing"
+)
+
+// distributedReaders returns the sum of the per-P reader counts of rw.
+func distributedReaders(rw *DistributedRWMutex) int64 {
+	var n int64
+	for _, c := range DistributedRWMutexCounts(rw) {
+		n += c
+	}
+	return n
+}
+
+// TestDistributedRWMutexExclusion runs readers on several Ps, which often
+// unlock on another P than they locked on, along with writers.
+func TestDistributedRWMutexExclusion(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+	var rw DistributedRWMutex
+	var active atomic.Int32 // readers, or -1 for a writer
+	var wg WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 2000; j++ {
+				switch (i + j) % 10 {
+				case 0:
+					rw.Lock()
+					if !active.CompareAndSwap(0, -1) {
+						t.Error("writer admitted with other holders")
+					}
+					if n := distributedReaders(&rw); n != 0 {
+						t.Errorf("%d readers counted while a writer holds the lock", n)
+					}
+					active.Store(0)
+					rw.Unlock()
+				case 1:
+					if rw.TryLock() {
+						if !active.CompareAndSwap(0, -1) {
+							t.Error("writer admitted by TryLock with other holders")
+						}
+						active.Store(0)
+						rw.Unlock()
+					}
+				case 2:
+					if rw.TryRLock() {
+						if active.Add(1) <= 0 {
+							t.Error("reader admitted by TryRLock with a writer")
+						}
+						active.Add(-1)
+						rw.RUnlock()
+					}
+				default:
+					rw.RLock()
+					if active.Add(1) <= 0 {
+						t.Error("reader admitted with a writer")
+					}
+					// Give the reader a chance to move to
+					// another P.
+					runtime.Gosched()
+					active.Add(-1)
+					rw.RUnlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	counts := DistributedRWMutexCounts(&rw)
+	if len(counts) == 0 {
+		t.Fatal("reader counts not allocated")
+	}
+	if n := distributedReaders(&rw); n != 0 {
+		t.Fatalf("reader counts %v sum to %d, want 0", counts, n)
+	}
+	if !rw.TryLock() {
+		t.Fatal("lock not free at the end")
+	}
+	rw.Unlock()
+}
+
+// TestDistributedRWMutexReaders checks that readers are counted until
+// they unlock, wherever they do.
+func TestDistributedRWMutexReaders(t *testing.T) {
+	var rw DistributedRWMutex
+	if counts := DistributedRWMutexCounts(&rw); counts != nil {
+		t.Fatalf("reader counts %v allocated before first use", counts)
+	}
+	const n = 5
+	for i := 0; i < n; i++ {
+		rw.RLock()
+	}
+	if got := distributedReaders(&rw); got != n {
+		t.Fatalf("%d readers counted, want %d", got, n)
+	}
+	if rw.TryLock() {
+		t.Fatal("TryLock with readers succeeded")
+	}
+	for i := 0; i < n; i++ {
+		rw.RUnlock()
+	}
+	if got := distributedReaders(&rw); got != 0 {
+		t.Fatalf("%d readers counted after RUnlock, want 0", got)
+	}
+}
+
+// rwLocker is the interface common to RWMutex and DistributedRWMutex.
+type rwLocker interface {
+	Locker
+	RLock()
+	RUnlock()
+}
+
+var writeRatios = []struct {
+	name       string
+	writeEvery int // acquisitions of a goroutine per write; 0 for none
+}{
+	{"NoWrites", 0},
+	{"WriteEvery1000", 1000},
+	{"WriteEvery100", 100},
+}
+
+// benchmarkReadMostly runs goroutines that take rw for reading in
+// parallel, and for writing at each of writeRatios.
+func benchmarkReadMostly(b *testing.B, newRWLocker func() rwLocker) {
+	for _, r := range writeRatios {
+		b.Run(r.name, func(b *testing.B) {
+			rw := newRWLocker()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					i++
+					if r.writeEvery > 0 && i%r.writeEvery == 0 {
+						rw.Lock()
+						delay(10)
+						rw.Unlock()
+						continue
+					}
+					rw.RLock()
+					delay(10)
+					rw.RUnlock()
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkReadMostlyRWMutex(b *testing.B) {
+	benchmarkReadMostly(b, func() rwLocker { return new(RWMutex) })
+}
+
+func BenchmarkReadMostlyDistributedRWMutex(b *testing.B) {
+	benchmarkReadMostly(b, func() rwLocker { return new(DistributedRWMutex) })
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import "sync/atomic"
+
+// MutexWaiters returns the number of waiters recorded in the state of m.
+func MutexWaiters(m *Mutex) int32 {
+	return atomic.LoadInt32(&m.state) >> mutexWaiterShift
+}
+
+// MutexStarving reports whether m is in starvation mode.
+func MutexStarving(m *Mutex) bool {
+	return atomic.LoadInt32(&m.state)&mutexStarving != 0
+}
+
+// DebugOwners reports whether the package was built with -tags syncdebug.
+const DebugOwners = debugOwners
+
+// PolicyMutexMutex returns the Mutex inside m.
+func PolicyMutexMutex(m *PolicyMutex) *Mutex {
+	return &m.mu
+}
+
+// PolicyMutexTickets returns the number of waiters of m with FairnessFIFO.
+func PolicyMutexTickets(m *PolicyMutex) uint32 {
+	m.lockQueue()
+	defer m.unlockQueue()
+	return m.waiters
+}
+
+// DistributedRWMutexCounts returns the per-P reader counts of rw, or nil
+// if they are not allocated yet.
+func DistributedRWMutexCounts(rw *DistributedRWMutex) []int64 {
+	shards := rw.shards.Load()
+	if shards == nil {
+		return nil
+	}
+	counts := make([]int64, len(*shards))
+	for i := range *shards {
+		counts[i] = (*shards)[i].readers.Load()
+	}
+	return counts
+}
+
+// NamedMutexMutex returns the Mutex inside m.
+func NamedMutexMutex(m *NamedMutex) *Mutex {
+	return &m.mu
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import "runtime"
+
+// goid returns the ID of the calling goroutine. The runtime does not
+// export it, so it is parsed from the header of the goroutine's stack
+// trace, which makes it too slow for the fast paths of the locks.
+// goid 返回调用者goroutine的ID。runtime没有导出它，因此从goroutine栈跟踪的头部解析得到，
+// 这使得它太慢，不能用在锁的快速路径上。
+func goid() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	return parseGoid(buf[:n])
+}
+
+// parseGoid returns the goroutine ID in the header of a stack trace,
+// "goroutine 18 [running]:".
+// parseGoid 返回栈跟踪头部"goroutine 18 [running]:"中的goroutine ID。
+func parseGoid(stack []byte) uint64 {
+	const prefix = "goroutine "
+	if len(stack) < len(prefix) || string(stack[:len(prefix)]) != prefix {
+		throw("sync: unexpected stack trace format")
+	}
+	var id uint64
+	for _, c := range stack[len(prefix):] {
+		if c < '0' || c > '9' {
+			break
+		}
+		id = id*10 + uint64(c-'0')
+	}
+	return id
+}
@@ -0,0 +1,319 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// Lock order validation.
+//
+// When the package is built with -tags lockdep, every blocking acquisition
+// of a Mutex or RWMutex, for reading or writing and including through
+// RLocker, records an edge from the class of each lock the goroutine
+// already holds to the class of the lock being acquired. Together the
+// edges form a global graph of the orders in which the classes of locks
+// have been taken. An acquisition that would close a cycle in that graph
+// is a potential deadlock, even if the goroutines involved never actually
+// met: it is reported once, with the stacks at which each edge of the
+// cycle was recorded, and the program continues. TryLock and TryRLock
+// cannot deadlock, so they add no edges, but locks acquired while holding
+// them do.
+//
+// Each lock is a class of its own, except that the NamedMutexes of a name
+// are one class, so that an inversion between two instances of a data
+// structure can be found by giving their locks the same name. The graph
+// keeps the locks that are classes of their own reachable, so their
+// memory is never reclaimed; the cost makes the mode suitable for tests
+// only.
+//
+// A lock may be released by another goroutine than the one that acquired
+// it, as the lock types permit; it is then no longer held by either.
+//
+// 加锁顺序校验。
+//
+// 当使用-tags lockdep构建本包时，每次阻塞地获取Mutex或RWMutex（无论读还是写，包括通过RLocker），
+// 都会记录从goroutine已经持有的每个锁的类别指向正在获取的锁的类别的一条边。所有的边共同构成一个全局图，
+// 描述了各类锁被获取的顺序。会在图中形成环的获取操作就是一个潜在的死锁，即使相关的goroutine实际上从未相遇：
+// 它会被报告一次，同时给出环上每条边被记录时的栈，然后程序继续运行。TryLock和TryRLock不会死锁，
+// 因此它们不添加边，但持有它们获取的锁时再获取的锁会添加边。
+//
+// 每个锁自成一个类别，但同名的NamedMutex属于同一个类别，因此给一个数据结构的不同实例的锁取相同的名称，
+// 就能发现它们之间的顺序颠倒。加锁顺序图使自成类别的锁保持可达，因此它们的内存永远不会被回收；
+// 这样的代价使得该模式只适合在测试中使用。
+//
+// 锁可以由获取它的goroutine以外的goroutine释放（锁类型允许这样做）；之后两者都不再持有该锁。
+
+// lockKey identifies a lock. The Mutex inside an RWMutex has the same
+// address as the RWMutex, hence the kind.
+// lockKey 标识一个锁。RWMutex内部的Mutex与RWMutex地址相同，因此需要区分类型。
+type lockKey struct {
+	p  unsafe.Pointer
+	rw bool
+}
+
+// lockClass identifies a class of locks, the nodes of the graph of lock
+// orders: the NamedMutexes of a name, or else a single lock.
+// lockClass 标识一类锁，即加锁顺序图中的节点：同名的NamedMutex，或者单个锁。
+type lockClass struct {
+	name string
+	p    unsafe.Pointer // if name is empty
+	rw   bool
+}
+
+// lockdepHeld is a lock held by a goroutine.
+// lockdepHeld 是一个goroutine持有的锁。
+type lockdepHeld struct {
+	key   lockKey
+	class lockClass
+	pcs   []uintptr // where it was acquired
+}
+
+// lockdepEdge records that a lock, acquired at fromPCs, was held when
+// another one was acquired at toPCs.
+// lockdepEdge 记录了一个在fromPCs处获取的锁，在另一个锁于toPCs处被获取时仍被持有。
+type lockdepEdge struct {
+	fromPCs, toPCs []uintptr
+}
+
+var lockdep struct {
+	mu    spinLock
+	held  map[uint64][]lockdepHeld // by goroutine ID
+	after map[lockClass]map[lockClass]*lockdepEdge
+	names map[uintptr]string // classes of NamedMutexes, by address
+}
+
+// lockdepDeclare puts m in the class name, until m is collected.
+// lockdepDeclare 将m归入名为name的类别，直到m被回收。
+func lockdepDeclare(m *NamedMutex, name string) {
+	lockdep.mu.lock()
+	if lockdep.names == nil {
+		lockdep.names = make(map[uintptr]string)
+	}
+	lockdep.names[uintptr(unsafe.Pointer(&m.mu))] = name
+	lockdep.mu.unlock()
+	runtime.SetFinalizer(m, func(m *NamedMutex) {
+		lockdep.mu.lock()
+		delete(lockdep.names, uintptr(unsafe.Pointer(&m.mu)))
+		lockdep.mu.unlock()
+	})
+}
+
+// lockdepSite returns the record of the lock key as acquired by the
+// caller.
+// lockdepSite 返回调用者获取锁key时的记录。
+func lockdepSite(key lockKey) lockdepHeld {
+	pcs := make([]uintptr, 32)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+	h := lockdepHeld{key: key, class: lockClass{rw: key.rw}, pcs: pcs}
+	lockdep.mu.lock()
+	h.class.name = lockdep.names[uintptr(key.p)]
+	lockdep.mu.unlock()
+	if h.class.name == "" {
+		h.class.p = key.p
+	}
+	return h
+}
+
+// lockdepFrames calls yield for the frames of pcs, a stack recorded by
+// lockdepSite, starting with the first one outside this package, until
+// yield returns false.
+// lockdepFrames 对pcs（由lockdepSite记录的栈）中的栈帧调用yield，从本包之外的第一个栈帧开始，直到yield返回false。
+func lockdepFrames(pcs []uintptr, yield func(runtime.Frame) bool) {
+	frames := runtime.CallersFrames(pcs)
+	self, more := frames.Next()
+	// The functions of this package share the prefix of lockdepSite.
+	// 本包的函数与lockdepSite有相同的前缀
+	pkg := self.Function[:len(self.Function)-len("lockdepSite")]
+	inside := true
+	for more {
+		var f runtime.Frame
+		f, more = frames.Next()
+		if inside && len(f.Function) > len(pkg) && f.Function[:len(pkg)] == pkg {
+			continue
+		}
+		inside = false
+		if !yield(f) {
+			return
+		}
+	}
+}
+
+// lockdepLocking checks the acquisition of the lock key by the caller of
+// the lock method that calls lockdepLocking, which is about to block on
+// it, and returns its record, to be passed to lockdepLocked once the lock
+// is acquired.
+// lockdepLocking 检查调用lockdepLocking的锁方法的调用者对锁key的获取（调用者即将在该锁上阻塞），
+// 并返回其记录，在获取锁之后传给lockdepLocked。
+func lockdepLocking(key lockKey) lockdepHeld {
+	h := lockdepSite(key)
+	g := goid()
+
+	var cycle []lockClass
+	var edges []*lockdepEdge
+	var holding lockdepHeld
+	lockdep.mu.lock()
+	for _, o := range lockdep.held[g] {
+		if o.class == h.class || lockdep.after[o.class][h.class] != nil {
+			// Known order: any inversion was reported when
+			// the edge was added.
+			// 已知的顺序：任何顺序颠倒都已在添加这条边时报告过
+			continue
+		}
+		if cycle == nil {
+			if path, pe := lockdepPath(h.class, o.class); path != nil {
+				cycle, edges, holding = path, pe, o
+			}
+		}
+		if lockdep.after == nil {
+			lockdep.after = make(map[lockClass]map[lockClass]*lockdepEdge)
+		}
+		if lockdep.after[o.class] == nil {
+			lockdep.after[o.class] = make(map[lockClass]*lockdepEdge)
+		}
+		lockdep.after[o.class][h.class] = &lockdepEdge{fromPCs: o.pcs, toPCs: h.pcs}
+	}
+	lockdep.mu.unlock()
+
+	if cycle != nil {
+		lockdepReport(h, holding, cycle, edges)
+	}
+	return h
+}
+
+// lockdepLocked records that the calling goroutine holds the lock of h.
+// lockdepLocked 记录调用者goroutine持有h所描述的锁。
+func lockdepLocked(h lockdepHeld) {
+	g := goid()
+	lockdep.mu.lock()
+	if lockdep.held == nil {
+		lockdep.held = make(map[uint64][]lockdepHeld)
+	}
+	lockdep.held[g] = append(lockdep.held[g], h)
+	lockdep.mu.unlock()
+}
+
+// lockdepPath returns a path from the class from to the class to in the
+// graph of lock orders, as the classes along it and the edges between
+// them, or nil if there is none. Called with lockdep.mu held.
+// lockdepPath 返回加锁顺序图中从类别from到类别to的一条路径，包括路径上的类别以及它们之间的边；
+// 如果不存在路径则返回nil。调用时必须持有lockdep.mu。
+func lockdepPath(from, to lockClass) ([]lockClass, []*lockdepEdge) {
+	// Breadth-first search, remembering how each class was reached.
+	// 广度优先搜索，并记住每个类别是如何到达的
+	prev := map[lockClass]lockClass{from: from}
+	queue := []lockClass{from}
+	for len(queue) > 0 {
+		k := queue[0]
+		queue = queue[1:]
+		if k == to {
+			var path []lockClass
+			for ; k != from; k = prev[k] {
+				path = append(path, k)
+			}
+			path = append(path, from)
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			edges := make([]*lockdepEdge, len(path)-1)
+			for i := range edges {
+				edges[i] = lockdep.after[path[i]][path[i+1]]
+			}
+			return path, edges
+		}
+		for next := range lockdep.after[k] {
+			if _, seen := prev[next]; !seen {
+				prev[next] = k
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// lockdepRelease records that the lock key is released, normally by the
+// goroutine that holds it, but possibly by another one.
+// lockdepRelease 记录锁key被释放，通常是由持有它的goroutine释放，但也可能是由其他goroutine释放。
+func lockdepRelease(key lockKey) {
+	g := goid()
+	lockdep.mu.lock()
+	if !lockdepForget(g, key) {
+		for o := range lockdep.held {
+			if lockdepForget(o, key) {
+				break
+			}
+		}
+	}
+	lockdep.mu.unlock()
+}
+
+// lockdepForget removes the lock key from those held by the goroutine g,
+// and reports whether it was one of them. Called with lockdep.mu held.
+// lockdepForget 从goroutine g持有的锁中移除锁key，并报告它是否在其中。调用时必须持有lockdep.mu。
+func lockdepForget(g uint64, key lockKey) bool {
+	held := lockdep.held[g]
+	for i := len(held) - 1; i >= 0; i-- {
+		if held[i].key == key {
+			held = append(held[:i], held[i+1:]...)
+			if len(held) == 0 {
+				delete(lockdep.held, g)
+			} else {
+				lockdep.held[g] = held
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// lockdepReport reports that acquiring the lock of h while holding the
+// lock holding inverts the order recorded along cycle, which leads from
+// the class of h back to the class of that lock.
+// lockdepReport 报告在持有锁holding时获取h所描述的锁，与沿cycle记录的顺序相反；
+// cycle从h的类别出发回到该锁的类别。
+func lockdepReport(h, holding lockdepHeld, cycle []lockClass, edges []*lockdepEdge) {
+	print("sync: potential deadlock: lock order inversion\n\n")
+	print("acquiring ")
+	printLockClass(h.class)
+	print(" at:\n")
+	printPCs(h.pcs)
+	print("while holding ")
+	printLockClass(holding.class)
+	print(", acquired at:\n")
+	printPCs(holding.pcs)
+	print("\nbut the reverse order was established before:\n")
+	for i, e := range edges {
+		print("\n")
+		printLockClass(cycle[i])
+		print(", acquired at:\n")
+		printPCs(e.fromPCs)
+		print("was held when acquiring ")
+		printLockClass(cycle[i+1])
+		print(" at:\n")
+		printPCs(e.toPCs)
+	}
+	print("\n")
+}
+
+func printLockClass(c lockClass) {
+	if c.rw {
+		print("RWMutex")
+	} else {
+		print("Mutex")
+	}
+	if c.name != "" {
+		print(" ", c.name)
+	} else {
+		print(" ", c.p)
+	}
+}
+
+func printPCs(pcs []uintptr) {
+	lockdepFrames(pcs, func(f runtime.Frame) bool {
+		print("\t", f.Function, "\n\t\t", f.File, ":", f.Line, "\n")
+		return true
+	})
+}
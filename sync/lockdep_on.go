@@ -0,0 +1,12 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build lockdep
+
+package sync
+
+// lockdepEnabled reports whether the lock order is validated; see
+// lockdep.go. Build with -tags lockdep to enable it.
+// lockdepEnabled 报告是否校验加锁顺序；参见lockdep.go。使用-tags lockdep构建以启用它。
+const lockdepEnabled = true
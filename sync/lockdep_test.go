@@ -0,0 +1,221 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build lockdep
+
+package sync_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	. "sync"
+	"testing"
+)
+
+var lockdepTests = []struct {
+	name      string
+	f         func()
+	reports   int  // potential deadlocks
+	handsOver bool // releases a lock in another goroutine
+}{
+	{
+		"AB-BA",
+		func() {
+			var a, b Mutex
+			a.Lock()
+			b.Lock()
+			b.Unlock()
+			a.Unlock()
+			inGoroutine(func() {
+				b.Lock()
+				a.Lock()
+				a.Unlock()
+				b.Unlock()
+			})
+		},
+		1, false,
+	},
+	{
+		"AB-BA through helpers",
+		func() {
+			var a, b Mutex
+			lockA := func() { a.Lock() }
+			lockB := func() { b.Lock() }
+			lockA()
+			lockB()
+			b.Unlock()
+			a.Unlock()
+			lockB()
+			lockA()
+			a.Unlock()
+			b.Unlock()
+		},
+		1, false,
+	},
+	{
+		"cycle of three with RWMutex",
+		func() {
+			var a Mutex
+			var b, c RWMutex
+			a.Lock()
+			b.RLock()
+			b.RUnlock()
+			a.Unlock()
+			b.RLock()
+			c.RLocker().Lock()
+			c.RUnlock()
+			b.RUnlock()
+			c.RLock()
+			a.Lock()
+			a.Unlock()
+			c.RUnlock()
+			c.Lock()
+			c.Unlock()
+		},
+		1, false,
+	},
+	{
+		"cross-instance inversion",
+		func() {
+			// x and y are one class: x before c, then c before y
+			// is an inversion.
+			x, y := NewNamedMutex("lockdep node"), NewNamedMutex("lockdep node")
+			var c Mutex
+			x.Lock()
+			c.Lock()
+			c.Unlock()
+			x.Unlock()
+			c.Lock()
+			y.Lock()
+			y.Unlock()
+			c.Unlock()
+		},
+		1, false,
+	},
+	{
+		"NamedMutex inversion",
+		func() {
+			p, q := NewNamedMutex("lockdep p"), NewNamedMutex("lockdep q")
+			p.Lock()
+			q.Lock()
+			q.Unlock()
+			p.Unlock()
+			q.Lock()
+			p.Lock()
+			p.Unlock()
+			q.Unlock()
+		},
+		1, false,
+	},
+	{
+		"PolicyMutex inversion with FairnessFIFO",
+		func() {
+			a := NewPolicyMutex(FairnessFIFO, 0)
+			var b Mutex
+			a.Lock()
+			b.Lock()
+			b.Unlock()
+			a.Unlock()
+			b.Lock()
+			a.Lock()
+			a.Unlock()
+			b.Unlock()
+		},
+		1, false,
+	},
+	{
+		"correct use",
+		func() {
+			x, y := NewNamedMutex("lockdep node"), NewNamedMutex("lockdep node")
+			var a, b Mutex
+			var rw RWMutex
+			for i := 0; i < 3; i++ {
+				// Locks of one class held together add no edge.
+				x.Lock()
+				y.Lock()
+				y.Unlock()
+				x.Unlock()
+				y.Lock()
+				x.Lock()
+				x.Unlock()
+				y.Unlock()
+
+				a.Lock()
+				b.Lock()
+				rw.RLock()
+				rw.RUnlock()
+				b.Unlock()
+				a.Unlock()
+				// TryLock cannot deadlock.
+				b.Lock()
+				if a.TryLock() {
+					a.Unlock()
+				}
+				b.Unlock()
+			}
+			c := NewCond(&a)
+			a.Lock()
+			go func() {
+				a.Lock()
+				c.Signal()
+				a.Unlock()
+			}()
+			c.Wait()
+			a.Unlock()
+		},
+		0, false,
+	},
+	{
+		"release by another goroutine",
+		func() {
+			var m, n Mutex
+			m.Lock()
+			inGoroutine(m.Unlock)
+			// m is no longer held: this adds no edge from m to n.
+			n.Lock()
+			n.Unlock()
+			n.Lock()
+			m.Lock()
+			m.Unlock()
+			n.Unlock()
+		},
+		0, true,
+	},
+}
+
+func init() {
+	if len(os.Args) == 3 && os.Args[1] == "TESTLOCKDEP" {
+		for _, test := range lockdepTests {
+			if test.name == os.Args[2] {
+				test.f()
+				fmt.Printf("test completed\n")
+				os.Exit(0)
+			}
+		}
+		fmt.Printf("unknown test\n")
+		os.Exit(0)
+	}
+}
+
+// TestLockdep runs each test in a child process, since the reports are
+// printed to standard error, and a process keeps the graph of lock orders
+// it has seen.
+func TestLockdep(t *testing.T) {
+	for _, test := range lockdepTests {
+		if test.handsOver && DebugOwners {
+			// Handing a lock over is fatal under -tags syncdebug.
+			continue
+		}
+		out, err := exec.Command(os.Args[0], "TESTLOCKDEP", test.name).CombinedOutput()
+		if err != nil || !strings.Contains(string(out), "test completed") {
+			t.Errorf("%s: %v\n%s", test.name, err, out)
+			continue
+		}
+		if n := strings.Count(string(out), "sync: potential deadlock"); n != test.reports {
+			t.Errorf("%s: %d reports of potential deadlocks, want %d:\n%s", test.name, n, test.reports, out)
+		}
+	}
+}
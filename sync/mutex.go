@@ -35,6 +35,12 @@ func fatal(string)
 // A failed call to TryLock does not establish any “synchronizes before”
 // relation at all.
 // Trylock的调用失败不会建立任何同步关系，仅是一种尝试获取锁的行为
+//
+// When built with -tags syncdebug, Mutex and RWMutex record the goroutine
+// holding them and report a goroutine that locks a mutex it already holds,
+// or unlocks one that another goroutine locked, with the stacks of both.
+// 使用-tags syncdebug构建时，Mutex和RWMutex会记录持有它们的goroutine，
+// 并在某个goroutine锁定自己已经持有的互斥锁，或者解锁由另一个goroutine锁定的互斥锁时报告错误，同时给出两者的栈。
 type Mutex struct {
 	state int32
 	sema  uint32
@@ -106,6 +112,12 @@ const (
 // blocks until the mutex is available.
 // Lock加锁，如果mutex已经被锁定，调用goroutine会阻塞直到mutex可用
 func (m *Mutex) Lock() {
+	if debugOwners {
+		defer m.debugLocked(m.debugLocking())
+	}
+	if lockdepEnabled {
+		defer lockdepLocked(lockdepLocking(lockKey{p: unsafe.Pointer(m)}))
+	}
 	// Fast path: grab unlocked mutex.
 	// 快速路径：获取未锁定的mutex
 	// 如果mutex未被锁定，通过CAS操作将mutex锁定，然后返回
@@ -118,7 +130,7 @@ func (m *Mutex) Lock() {
 	}
 	// Slow path (outlined so that the fast path can be inlined)
 	// 慢速路径（慢速路径被提取出来，以便快速路径可以内联）
-	m.lockSlow()
+	m.lockSlow(nil, starvationThresholdNs)
 }
 
 // TryLock tries to lock m and reports whether it succeeded.
@@ -150,10 +162,22 @@ func (m *Mutex) TryLock() bool {
 	if race.Enabled {
 		race.Acquire(unsafe.Pointer(m))
 	}
+	if debugOwners {
+		m.debugLocked(currentSite())
+	}
+	if lockdepEnabled {
+		lockdepLocked(lockdepSite(lockKey{p: unsafe.Pointer(m)}))
+	}
 	return true
 }
 
-func (m *Mutex) lockSlow() {
+// lockSlow waits for and acquires m. A waiter that has not acquired m
+// after starvationThreshold nanoseconds switches m to starvation mode.
+// If prof is not nil, the spinning iterations and switches into
+// starvation mode are recorded in it.
+// lockSlow 等待并获取m。等待了starvationThreshold纳秒仍未获取到m的等待者会将m切换到饥饿模式。
+// 如果prof不为nil，自旋次数和切换到饥饿模式的次数会被记录在其中。
+func (m *Mutex) lockSlow(prof *mutexProfile, starvationThreshold int64) {
 	var waitStartTime int64
 	starving := false
 	awoke := false
@@ -178,6 +202,9 @@ func (m *Mutex) lockSlow() {
 			// 自旋一次
 			runtime_doSpin()
 			iter++
+			if prof != nil {
+				prof.spins.Add(1)
+			}
 			// 重新获取state的值
 			old = m.state
 			continue
@@ -216,6 +243,9 @@ func (m *Mutex) lockSlow() {
 		}
 		// 这里相当于将所有waiter唤醒，然后竞争mutext?
 		if atomic.CompareAndSwapInt32(&m.state, old, new) {
+			if prof != nil && new&^old&mutexStarving != 0 {
+				prof.starvations.Add(1)
+			}
 			// 非锁、非饥饿模式，直接返回
 			if old&(mutexLocked|mutexStarving) == 0 {
 				break // locked the mutex with CAS
@@ -227,7 +257,7 @@ func (m *Mutex) lockSlow() {
 				waitStartTime = runtime_nanotime()
 			}
 			runtime_SemacquireMutex(&m.sema, queueLifo, 1)
-			starving = starving || runtime_nanotime()-waitStartTime > starvationThresholdNs
+			starving = starving || runtime_nanotime()-waitStartTime > starvationThreshold
 			old = m.state
 			if old&mutexStarving != 0 {
 				// If this goroutine was woken and mutex is in starvation mode,
@@ -281,6 +311,12 @@ func (m *Mutex) lockSlow() {
 //
 // 锁定的mutex不与特定的goroutine关联。允许一个goroutine锁定mutex，然后安排另一个goroutine解锁它。
 func (m *Mutex) Unlock() {
+	if debugOwners {
+		m.debugUnlock()
+	}
+	if lockdepEnabled {
+		lockdepRelease(lockKey{p: unsafe.Pointer(m)})
+	}
 	if race.Enabled {
 		_ = m.state
 		// 释放锁的指针
@@ -0,0 +1,125 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// Provided by runtime via linkname.
+// The time package cannot be imported here, since it depends on sync.
+// 由runtime通过linkname提供。这里不能导入time包，因为time依赖于sync。
+//
+//go:linkname runtime_timeSleep time.Sleep
+func runtime_timeSleep(ns int64)
+
+// LockContext locks m like Lock, unless ctx is done before the lock
+// becomes available. It reports whether the lock was acquired; if it
+// returns false, m is not held by the caller. ctx is usually a
+// context.Context, which this package cannot refer to.
+// LockContext 像Lock一样锁定m，除非在锁可用之前ctx已经完成。它报告是否获取到了锁；
+// 如果返回false，调用者并没有持有m。ctx通常是一个context.Context，本包无法直接引用它。
+func (m *Mutex) LockContext(ctx interface{ Done() <-chan struct{} }) bool {
+	if m.TryLock() {
+		return true
+	}
+	done := ctx.Done()
+	if done == nil {
+		// ctx can never be canceled.
+		// ctx 永远不会被取消
+		m.Lock()
+		return true
+	}
+	select {
+	case <-done:
+		return false
+	default:
+	}
+	return m.lockCancelable(done)
+}
+
+// LockTimeout locks m like Lock, but waits at most ns nanoseconds for the
+// lock to become available; pass int64(d) for a time.Duration d. It
+// reports whether the lock was acquired.
+// LockTimeout 像Lock一样锁定m，但最多等待ns纳秒让锁变为可用；对于time.Duration类型的d，传入int64(d)即可。
+// 它报告是否获取到了锁。
+func (m *Mutex) LockTimeout(ns int64) bool {
+	if m.TryLock() {
+		return true
+	}
+	if ns <= 0 {
+		return false
+	}
+	expired := make(chan struct{})
+	go func() {
+		runtime_timeSleep(ns)
+		close(expired)
+	}()
+	return m.lockCancelable(expired)
+}
+
+// States of a cancelable lock request.
+// 可取消的加锁请求的状态。
+const (
+	lockWaiting   = iota // the waiter is parked in lockSlow
+	lockAcquired         // the waiter got the lock for the caller
+	lockAbandoned        // the caller gave up
+)
+
+// lockCancelable locks m, or gives up once cancel is closed.
+// lockCancelable 锁定m，或者在cancel被关闭时放弃。
+//
+// The lock is requested by a helper goroutine that parks in lockSlow
+// like any other waiter, so the waiter count and the mutexWoken and
+// mutexStarving bits in m.state are maintained by the usual code paths,
+// and the starvation threshold applies to it as well. The runtime's
+// semaphores provide no way to remove a parked goroutine from the wait
+// queue, so when the caller gives up, the helper keeps its place: once
+// it is handed the lock it releases it at once, which wakes the next
+// waiter exactly as the caller's Unlock would have.
+// 加锁请求由一个辅助goroutine发出，它像其他等待者一样在lockSlow中挂起，因此m.state中的等待者计数
+// 以及mutexWoken和mutexStarving位都由通常的代码路径维护，饥饿阈值同样适用于它。
+// runtime的信号量无法将挂起的goroutine从等待队列中移除，因此调用者放弃时，辅助goroutine保留其位置：
+// 一旦它得到锁就立即释放，这会像调用者的Unlock一样唤醒下一个等待者。
+func (m *Mutex) lockCancelable(cancel <-chan struct{}) bool {
+	var site lockSite
+	if debugOwners {
+		site = m.debugLocking()
+	}
+	var state atomic.Int32 // lockWaiting, lockAcquired or lockAbandoned
+	acquired := make(chan struct{})
+	go func() {
+		m.lockSlow(nil, starvationThresholdNs)
+		if !state.CompareAndSwap(lockWaiting, lockAcquired) {
+			// The caller is gone; pass the lock on.
+			// 调用者已经离开；将锁传递下去
+			m.Unlock()
+			return
+		}
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+	case <-cancel:
+		// The helper may have acquired the lock in the meantime,
+		// in which case it belongs to the caller.
+		// 辅助goroutine可能在此期间已经获取了锁，这时锁属于调用者
+		if state.CompareAndSwap(lockWaiting, lockAbandoned) {
+			return false
+		}
+	}
+	// The lock is the caller's, not the helper's.
+	// 锁属于调用者，而不是辅助goroutine
+	if debugOwners {
+		m.debugLocked(site)
+	}
+	if lockdepEnabled {
+		// Like TryLock, a cancelable Lock cannot deadlock.
+		// 和TryLock一样，可取消的Lock不会死锁
+		lockdepLocked(lockdepSite(lockKey{p: unsafe.Pointer(m)}))
+	}
+	return true
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync_test
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	. "sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForWaiters waits until m records n waiters.
+func waitForWaiters(t *testing.T, m *Mutex, n int32) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for MutexWaiters(m) != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d waiters, want %d", MutexWaiters(m), n)
+		}
+		time.Sleep(100 * time.Microsecond)
+	}
+}
+
+// checkUnlocked checks that m ends up unlocked, without waiters, once the
+// helper goroutines of LockContext calls are gone, which includes those
+// of abandoned calls passing the lock on.
+func checkUnlocked(t *testing.T, m *Mutex) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if !strings.Contains(string(buf[:n]), ".lockCancelable.func") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("LockContext helpers still running:\n%s", buf[:n])
+		}
+		time.Sleep(100 * time.Microsecond)
+	}
+	if w := MutexWaiters(m); w != 0 {
+		t.Fatalf("%d waiters left", w)
+	}
+	if !m.TryLock() {
+		t.Fatal("TryLock of unlocked Mutex failed")
+	}
+	m.Unlock()
+}
+
+// inGoroutine runs f in a new goroutine and waits for it.
+func inGoroutine(f func()) {
+	done := make(chan bool)
+	go func() {
+		f()
+		close(done)
+	}()
+	<-done
+}
+
+func TestLockContext(t *testing.T) {
+	var m Mutex
+	if !m.LockContext(context.Background()) {
+		t.Fatal("LockContext of unlocked Mutex failed")
+	}
+
+	// Done before the call.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if m.LockContext(ctx) {
+		t.Fatal("LockContext with canceled context succeeded")
+	}
+
+	// Canceled while waiting. The abandoned request passes the lock on.
+	ctx, cancel = context.WithCancel(context.Background())
+	result := make(chan bool)
+	go func() { result <- m.LockContext(ctx) }()
+	waitForWaiters(t, &m, 1)
+	cancel()
+	if <-result {
+		t.Fatal("LockContext succeeded after cancel")
+	}
+	m.Unlock()
+	checkUnlocked(t, &m)
+
+	// Unlocked while waiting.
+	m.Lock()
+	go func() {
+		ok := m.LockContext(context.Background())
+		if ok {
+			m.Unlock()
+		}
+		result <- ok
+	}()
+	waitForWaiters(t, &m, 1)
+	m.Unlock()
+	if !<-result {
+		t.Fatal("LockContext failed")
+	}
+	checkUnlocked(t, &m)
+}
+
+func TestLockTimeout(t *testing.T) {
+	var m Mutex
+	if !m.LockTimeout(0) {
+		t.Fatal("LockTimeout of unlocked Mutex failed")
+	}
+	if m.LockTimeout(0) || m.LockTimeout(-1) {
+		t.Fatal("LockTimeout without time to wait succeeded")
+	}
+
+	// The holder may not wait for the lock itself under -tags syncdebug.
+	const d = 10 * time.Millisecond
+	result := make(chan bool)
+	start := time.Now()
+	go func() { result <- m.LockTimeout(int64(d)) }()
+	if <-result {
+		t.Fatal("LockTimeout of locked Mutex succeeded")
+	}
+	if elapsed := time.Since(start); elapsed < d {
+		t.Fatalf("LockTimeout gave up after %v, want at least %v", elapsed, d)
+	}
+	m.Unlock()
+	checkUnlocked(t, &m)
+
+	m.Lock()
+	go func() {
+		ok := m.LockTimeout(int64(time.Minute))
+		if ok {
+			m.Unlock()
+		}
+		result <- ok
+	}()
+	waitForWaiters(t, &m, 1)
+	m.Unlock()
+	if !<-result {
+		t.Fatal("LockTimeout failed")
+	}
+	checkUnlocked(t, &m)
+}
+
+// TestLockContextStarving cancels waiters of a Mutex in starvation mode,
+// to which Unlock hands the lock off directly. The abandoned requests
+// must pass it on to the next waiter.
+func TestLockContextStarving(t *testing.T) {
+	var m Mutex
+	m.Lock()
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	defer cancel1()
+	first := make(chan bool, 1)
+	start := func() {
+		go func() {
+			ok := m.LockContext(ctx1)
+			if ok {
+				m.Unlock()
+			}
+			first <- ok
+		}()
+	}
+	start()
+	// Wake the waiter after it waited for more than 1ms, and take the
+	// lock before it runs, until it switches m to starvation mode.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		time.Sleep(2 * time.Millisecond)
+		if MutexStarving(&m) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Mutex did not switch to starvation mode")
+		}
+		m.Unlock()
+		m.Lock()
+		select {
+		case <-first:
+			// The waiter won; try again.
+			start()
+		default:
+		}
+	}
+
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	second := make(chan bool)
+	go func() { second <- m.LockContext(ctx2) }()
+	waitForWaiters(t, &m, 2)
+	cancel2()
+	if <-second {
+		t.Fatal("second LockContext succeeded after cancel")
+	}
+	cancel1()
+	if <-first {
+		t.Fatal("first LockContext succeeded after cancel")
+	}
+
+	third := make(chan bool)
+	go func() {
+		m.Lock()
+		m.Unlock()
+		close(third)
+	}()
+	waitForWaiters(t, &m, 3)
+	m.Unlock()
+	select {
+	case <-third:
+	case <-time.After(5 * time.Second):
+		t.Fatal("abandoned LockContext calls did not pass the lock on")
+	}
+	checkUnlocked(t, &m)
+	if MutexStarving(&m) {
+		t.Fatal("Mutex left in starvation mode")
+	}
+}
+
+// TestLockContextStress mixes Lock with LockContext and LockTimeout calls
+// that often give up, and checks mutual exclusion and the final state.
+func TestLockContextStress(t *testing.T) {
+	var m Mutex
+	var inside atomic.Int32
+	const n, iters = 12, 300
+	var wg WaitGroup
+	for g := 0; g < n; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iters; i++ {
+				var ok bool
+				switch g % 3 {
+				case 0:
+					m.Lock()
+					ok = true
+				case 1:
+					ok = m.LockTimeout(int64(time.Duration(i%5) * 20 * time.Microsecond))
+				case 2:
+					ctx, cancel := context.WithTimeout(context.Background(), time.Duration(i%7)*10*time.Microsecond)
+					ok = m.LockContext(ctx)
+					cancel()
+				}
+				if !ok {
+					continue
+				}
+				if inside.Add(1) != 1 {
+					t.Error("mutual exclusion violated")
+				}
+				delay(1000)
+				inside.Add(-1)
+				m.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	checkUnlocked(t, &m)
+}
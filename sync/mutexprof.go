@@ -0,0 +1,316 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import (
+	"sync/atomic"
+	"unsafe"
+
+	"internal/race"
+)
+
+// A NamedMutex is a Mutex that records how it is contended, so that hot
+// locks can be found by name. The statistics of all NamedMutexes with the
+// same name are added up; they are available from Stats, MutexProfile and,
+// in the format read by the pprof tool, AppendMutexProfile.
+// NamedMutex 是一个记录自身竞争情况的Mutex，从而可以按名称找出热点锁。所有同名NamedMutex的统计数据会被累加；
+// 可以通过Stats、MutexProfile以及（以pprof工具可读取的格式）AppendMutexProfile获取这些数据。
+//
+// Profiling costs a clock reading per Lock and Unlock, so it is opt-in:
+// a NamedMutex must be created by NewNamedMutex, and the zero value is a
+// plain unlocked mutex that records nothing.
+// 性能分析的代价是每次Lock和Unlock都要读取一次时钟，因此需要显式启用：
+// NamedMutex必须由NewNamedMutex创建，其零值是一个不记录任何数据的普通未锁定互斥锁。
+//
+// A NamedMutex must not be copied after first use.
+// NamedMutex 在第一次使用后不能被复制。
+type NamedMutex struct {
+	mu       Mutex
+	prof     *mutexProfile
+	lockedAt int64 // runtime_nanotime when the current holder acquired mu
+}
+
+// mutexProfile holds the statistics shared by the NamedMutexes of a name.
+// mutexProfile 保存同名NamedMutex共享的统计数据。
+type mutexProfile struct {
+	name        string
+	acquired    atomic.Int64
+	contended   atomic.Int64
+	spins       atomic.Int64
+	waitTime    atomic.Int64
+	holdTime    atomic.Int64
+	starvations atomic.Int64
+}
+
+// MutexStats is a snapshot of the statistics of the NamedMutexes of a
+// name. Times are in nanoseconds.
+// MutexStats 是某个名称的NamedMutex统计数据的快照。时间以纳秒为单位。
+type MutexStats struct {
+	Name        string
+	Acquired    int64 // successful calls to Lock and TryLock
+	Contended   int64 // calls to Lock that had to wait
+	Spins       int64 // iterations of active spinning while waiting
+	WaitTime    int64 // time spent waiting in Lock
+	HoldTime    int64 // time the mutexes were held
+	Starvations int64 // switches into starvation mode
+}
+
+// mutexProfiles is the registry of profiles, one per name.
+// mutexProfiles 是性能分析数据的注册表，每个名称一项。
+var mutexProfiles struct {
+	mu     Mutex
+	byName map[string]*mutexProfile
+	list   []*mutexProfile // in order of registration
+}
+
+// NewNamedMutex returns an unlocked NamedMutex whose statistics are
+// recorded under name. The statistics of a name are kept for the lifetime
+// of the program.
+// NewNamedMutex 返回一个未锁定的NamedMutex，其统计数据记录在name名下。每个名称的统计数据在程序的整个生命周期内都会保留。
+func NewNamedMutex(name string) *NamedMutex {
+	mutexProfiles.mu.Lock()
+	defer mutexProfiles.mu.Unlock()
+	p := mutexProfiles.byName[name]
+	if p == nil {
+		if mutexProfiles.byName == nil {
+			mutexProfiles.byName = make(map[string]*mutexProfile)
+		}
+		p = &mutexProfile{name: name}
+		mutexProfiles.byName[name] = p
+		mutexProfiles.list = append(mutexProfiles.list, p)
+	}
+	m := &NamedMutex{prof: p}
+	if lockdepEnabled {
+		lockdepDeclare(m, name)
+	}
+	return m
+}
+
+// Lock locks m; see Mutex.Lock.
+// Lock 锁定m；参见Mutex.Lock。
+func (m *NamedMutex) Lock() {
+	p := m.prof
+	if p == nil {
+		m.mu.Lock()
+		return
+	}
+	if debugOwners {
+		defer m.mu.debugLocked(m.mu.debugLocking())
+	}
+	if lockdepEnabled {
+		defer lockdepLocked(lockdepLocking(lockKey{p: unsafe.Pointer(&m.mu)}))
+	}
+	now := runtime_nanotime()
+	if atomic.CompareAndSwapInt32(&m.mu.state, 0, mutexLocked) {
+		if race.Enabled {
+			race.Acquire(unsafe.Pointer(&m.mu))
+		}
+	} else {
+		m.mu.lockSlow(p, starvationThresholdNs)
+		start := now
+		now = runtime_nanotime()
+		p.contended.Add(1)
+		p.waitTime.Add(now - start)
+	}
+	p.acquired.Add(1)
+	m.lockedAt = now
+}
+
+// TryLock tries to lock m and reports whether it succeeded; see
+// Mutex.TryLock.
+// TryLock 尝试锁定m并报告是否成功；参见Mutex.TryLock。
+func (m *NamedMutex) TryLock() bool {
+	if !m.mu.TryLock() {
+		return false
+	}
+	if p := m.prof; p != nil {
+		p.acquired.Add(1)
+		m.lockedAt = runtime_nanotime()
+	}
+	return true
+}
+
+// Unlock unlocks m; see Mutex.Unlock.
+// Unlock 解锁m；参见Mutex.Unlock。
+func (m *NamedMutex) Unlock() {
+	if p := m.prof; p != nil && m.lockedAt != 0 {
+		p.holdTime.Add(runtime_nanotime() - m.lockedAt)
+		m.lockedAt = 0
+	}
+	m.mu.Unlock()
+}
+
+// Stats returns the statistics recorded under the name of m.
+// Stats 返回记录在m的名称下的统计数据。
+func (m *NamedMutex) Stats() MutexStats {
+	if m.prof == nil {
+		return MutexStats{}
+	}
+	return m.prof.snapshot()
+}
+
+func (p *mutexProfile) snapshot() MutexStats {
+	return MutexStats{
+		Name:        p.name,
+		Acquired:    p.acquired.Load(),
+		Contended:   p.contended.Load(),
+		Spins:       p.spins.Load(),
+		WaitTime:    p.waitTime.Load(),
+		HoldTime:    p.holdTime.Load(),
+		Starvations: p.starvations.Load(),
+	}
+}
+
+// MutexProfile returns the statistics of every name used with
+// NewNamedMutex, in the order the names were first used.
+// MutexProfile 返回每个用于NewNamedMutex的名称的统计数据，按照名称首次使用的顺序排列。
+func MutexProfile() []MutexStats {
+	mutexProfiles.mu.Lock()
+	list := mutexProfiles.list
+	mutexProfiles.mu.Unlock()
+	stats := make([]MutexStats, len(list))
+	for i, p := range list {
+		stats[i] = p.snapshot()
+	}
+	return stats
+}
+
+// AppendMutexProfile appends to b the statistics returned by MutexProfile,
+// encoded as a profile.proto message that can be read by the pprof tool,
+// and returns the extended buffer. Each name appears as a function of its
+// own, and as the value of a "mutex" label. This package cannot depend on
+// io, so the caller writes the result, for example to a file for
+// "go tool pprof".
+// AppendMutexProfile 将MutexProfile返回的统计数据编码为pprof工具可读取的profile.proto消息，追加到b中，
+// 并返回扩展后的缓冲区。每个名称都作为一个独立的函数出现，同时作为"mutex"标签的值。
+// 本包不能依赖io，因此由调用者写出结果，例如写到文件中供"go tool pprof"使用。
+func AppendMutexProfile(b []byte) []byte {
+	stats := MutexProfile()
+
+	// String table; index 0 must be the empty string.
+	// 字符串表；索引0必须是空字符串
+	strs := []string{""}
+	index := make(map[string]int64)
+	str := func(s string) int64 {
+		if i, ok := index[s]; ok {
+			return i
+		}
+		i := int64(len(strs))
+		strs = append(strs, s)
+		index[s] = i
+		return i
+	}
+
+	sampleTypes := [...]struct{ typ, unit string }{
+		{"contentions", "count"},
+		{"delay", "nanoseconds"},
+		{"acquisitions", "count"},
+		{"hold", "nanoseconds"},
+		{"spins", "count"},
+		{"starvations", "count"},
+	}
+	const (
+		tagSampleType   = 1
+		tagSample       = 2
+		tagLocation     = 4
+		tagFunction     = 5
+		tagStringTable  = 6
+		tagPeriodType   = 11
+		tagPeriod       = 12
+		tagDefaultType  = 14
+		tagValueType    = 1 // ValueType.type
+		tagValueUnit    = 2 // ValueType.unit
+		tagSampleLocID  = 1 // Sample.location_id
+		tagSampleValue  = 2 // Sample.value
+		tagSampleLabel  = 3 // Sample.label
+		tagLabelKey     = 1 // Label.key
+		tagLabelStr     = 2 // Label.str
+		tagLocationID   = 1 // Location.id
+		tagLocationLine = 4 // Location.line
+		tagLineFuncID   = 1 // Line.function_id
+		tagFunctionID   = 1 // Function.id
+		tagFunctionName = 2 // Function.name
+	)
+
+	var e protoEncoder
+	for _, st := range sampleTypes {
+		var vt protoEncoder
+		vt.int64(tagValueType, str(st.typ))
+		vt.int64(tagValueUnit, str(st.unit))
+		e.message(tagSampleType, &vt)
+	}
+	for i, s := range stats {
+		id := uint64(i + 1)
+
+		var label protoEncoder
+		label.int64(tagLabelKey, str("mutex"))
+		label.int64(tagLabelStr, str(s.Name))
+		var sample protoEncoder
+		sample.uint64(tagSampleLocID, id)
+		for _, v := range [...]int64{s.Contended, s.WaitTime, s.Acquired, s.HoldTime, s.Spins, s.Starvations} {
+			sample.int64(tagSampleValue, v)
+		}
+		sample.message(tagSampleLabel, &label)
+		e.message(tagSample, &sample)
+
+		var line protoEncoder
+		line.uint64(tagLineFuncID, id)
+		var loc protoEncoder
+		loc.uint64(tagLocationID, id)
+		loc.message(tagLocationLine, &line)
+		e.message(tagLocation, &loc)
+
+		var fn protoEncoder
+		fn.uint64(tagFunctionID, id)
+		fn.int64(tagFunctionName, str(s.Name))
+		e.message(tagFunction, &fn)
+	}
+	var pt protoEncoder
+	pt.int64(tagValueType, str("contentions"))
+	pt.int64(tagValueUnit, str("count"))
+	e.message(tagPeriodType, &pt)
+	e.int64(tagPeriod, 1)
+	e.int64(tagDefaultType, str("delay"))
+	for _, s := range strs {
+		e.string(tagStringTable, s)
+	}
+	return append(b, e.b...)
+}
+
+// protoEncoder is a minimal protocol buffer encoder, enough to write a
+// profile.proto message.
+// protoEncoder 是一个最小化的protocol buffer编码器，足以写出profile.proto消息。
+type protoEncoder struct {
+	b []byte
+}
+
+func (e *protoEncoder) varint(x uint64) {
+	for x >= 0x80 {
+		e.b = append(e.b, byte(x)|0x80)
+		x >>= 7
+	}
+	e.b = append(e.b, byte(x))
+}
+
+func (e *protoEncoder) uint64(tag int, x uint64) {
+	e.varint(uint64(tag)<<3 | 0) // wire type varint
+	e.varint(x)
+}
+
+func (e *protoEncoder) int64(tag int, x int64) {
+	e.uint64(tag, uint64(x))
+}
+
+func (e *protoEncoder) string(tag int, s string) {
+	e.varint(uint64(tag)<<3 | 2) // wire type length-delimited
+	e.varint(uint64(len(s)))
+	e.b = append(e.b, s...)
+}
+
+func (e *protoEncoder) message(tag int, m *protoEncoder) {
+	e.varint(uint64(tag)<<3 | 2)
+	e.varint(uint64(len(m.b)))
+	e.b = append(e.b, m.b...)
+}
@@ -0,0 +1,361 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync_test
+
+import (
+	"fmt"
+	. "sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// The statistics of a name are kept for the lifetime of the program, so
+// each test uses names of its own, even when run several times.
+
+var namedMutexes atomic.Int32
+
+// newName returns a name that has not been used with NewNamedMutex.
+func newName(t *testing.T) string {
+	return fmt.Sprintf("%s %d", t.Name(), namedMutexes.Add(1))
+}
+
+func TestNamedMutexAcquired(t *testing.T) {
+	name := newName(t)
+	m := NewNamedMutex(name)
+	for i := 0; i < 10; i++ {
+		m.Lock()
+		m.Unlock()
+	}
+	if !m.TryLock() {
+		t.Fatal("TryLock of unlocked NamedMutex failed")
+	}
+	var failed bool
+	inGoroutine(func() { failed = !m.TryLock() })
+	if !failed {
+		t.Fatal("TryLock of locked NamedMutex succeeded")
+	}
+	m.Unlock()
+	s := m.Stats()
+	if s.Name != name || s.Acquired != 11 || s.Contended != 0 || s.WaitTime != 0 || s.Starvations != 0 {
+		t.Fatalf("Stats = %+v, want 11 acquisitions without contention", s)
+	}
+}
+
+func TestNamedMutexContended(t *testing.T) {
+	m := NewNamedMutex(newName(t))
+	const d = 10 * time.Millisecond
+	m.Lock()
+	done := make(chan bool)
+	go func() {
+		m.Lock()
+		m.Unlock()
+		close(done)
+	}()
+	waitForWaiters(t, NamedMutexMutex(m), 1)
+	time.Sleep(d)
+	m.Unlock()
+	<-done
+	s := m.Stats()
+	if s.Acquired != 2 || s.Contended != 1 {
+		t.Fatalf("Stats = %+v, want 2 acquisitions, 1 contended", s)
+	}
+	if s.WaitTime < int64(d) {
+		t.Fatalf("WaitTime = %v, want at least %v", time.Duration(s.WaitTime), d)
+	}
+}
+
+func TestNamedMutexHoldTime(t *testing.T) {
+	m := NewNamedMutex(newName(t))
+	const d = 5 * time.Millisecond
+	m.Lock()
+	time.Sleep(d)
+	m.Unlock()
+	if !m.TryLock() {
+		t.Fatal("TryLock of unlocked NamedMutex failed")
+	}
+	time.Sleep(d)
+	m.Unlock()
+	if s := m.Stats(); s.HoldTime < int64(2*d) {
+		t.Fatalf("HoldTime = %v, want at least %v", time.Duration(s.HoldTime), 2*d)
+	}
+}
+
+// TestNamedMutexStarvations makes a waiter switch the mutex to starvation
+// mode, as in TestLockContextStarving.
+func TestNamedMutexStarvations(t *testing.T) {
+	m := NewNamedMutex(newName(t))
+	m.Lock()
+	waiter := make(chan bool, 1)
+	start := func() {
+		go func() {
+			m.Lock()
+			m.Unlock()
+			waiter <- true
+		}()
+	}
+	start()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		time.Sleep(2 * time.Millisecond)
+		if MutexStarving(NamedMutexMutex(m)) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("NamedMutex did not switch to starvation mode")
+		}
+		m.Unlock()
+		m.Lock()
+		select {
+		case <-waiter:
+			start()
+		default:
+		}
+	}
+	m.Unlock()
+	<-waiter
+	if s := m.Stats(); s.Starvations < 1 {
+		t.Fatalf("Stats = %+v, want at least 1 starvation", s)
+	}
+}
+
+func TestNamedMutexShared(t *testing.T) {
+	name := newName(t)
+	a, b := NewNamedMutex(name), NewNamedMutex(name)
+	a.Lock()
+	a.Unlock()
+	b.Lock()
+	b.Unlock()
+	if sa, sb := a.Stats(), b.Stats(); sa != sb || sa.Acquired != 2 {
+		t.Fatalf("Stats = %+v and %+v, want the same, with 2 acquisitions", sa, sb)
+	}
+
+	var z NamedMutex
+	z.Lock()
+	z.Unlock()
+	if s := z.Stats(); s != (MutexStats{}) {
+		t.Fatalf("Stats of zero NamedMutex = %+v, want none", s)
+	}
+}
+
+func TestMutexProfile(t *testing.T) {
+	names := []string{newName(t), newName(t)}
+	for _, name := range names {
+		NewNamedMutex(name).Lock()
+	}
+	var got []string
+	for _, s := range MutexProfile() {
+		if s.Name == names[0] || s.Name == names[1] {
+			got = append(got, s.Name)
+			if s.Acquired != 1 {
+				t.Errorf("%s: Acquired = %d, want 1", s.Name, s.Acquired)
+			}
+		}
+	}
+	if fmt.Sprint(got) != fmt.Sprint(names) {
+		t.Fatalf("MutexProfile names %q, want %q in order of registration", got, names)
+	}
+}
+
+// protoField is a field of a protocol buffer message.
+type protoField struct {
+	tag   int
+	value uint64 // for varints
+	bytes []byte // for length-delimited fields
+}
+
+// decodeProto decodes the fields of a message that only uses varints and
+// length-delimited fields.
+func decodeProto(b []byte) ([]protoField, error) {
+	varint := func() (uint64, error) {
+		var x uint64
+		for shift := 0; shift < 64; shift += 7 {
+			if len(b) == 0 {
+				return 0, fmt.Errorf("truncated varint")
+			}
+			c := b[0]
+			b = b[1:]
+			x |= uint64(c&0x7f) << shift
+			if c < 0x80 {
+				return x, nil
+			}
+		}
+		return 0, fmt.Errorf("varint too long")
+	}
+	var fields []protoField
+	for len(b) > 0 {
+		key, err := varint()
+		if err != nil {
+			return nil, err
+		}
+		f := protoField{tag: int(key >> 3)}
+		switch key & 7 {
+		case 0:
+			if f.value, err = varint(); err != nil {
+				return nil, err
+			}
+		case 2:
+			n, err := varint()
+			if err != nil {
+				return nil, err
+			}
+			if n > uint64(len(b)) {
+				return nil, fmt.Errorf("field %d: length %d past end", f.tag, n)
+			}
+			f.bytes, b = b[:n], b[n:]
+		default:
+			return nil, fmt.Errorf("field %d: unexpected wire type %d", f.tag, key&7)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// mustDecodeProto is decodeProto for messages nested in one that decoded.
+func mustDecodeProto(t *testing.T, b []byte) []protoField {
+	t.Helper()
+	fields, err := decodeProto(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fields
+}
+
+func TestAppendMutexProfile(t *testing.T) {
+	name := newName(t)
+	m := NewNamedMutex(name)
+	m.Lock()
+	time.Sleep(time.Millisecond)
+	m.Unlock()
+	want := m.Stats()
+
+	prefix := []byte("prefix")
+	b := AppendMutexProfile(prefix)
+	if string(b[:len(prefix)]) != string(prefix) {
+		t.Fatalf("AppendMutexProfile did not append to its argument")
+	}
+	fields, err := decodeProto(b[len(prefix):])
+	if err != nil {
+		t.Fatalf("decoding profile: %v", err)
+	}
+
+	var strs []string
+	for _, f := range fields {
+		if f.tag == 6 { // string_table
+			strs = append(strs, string(f.bytes))
+		}
+	}
+	str := func(i uint64) string {
+		if i >= uint64(len(strs)) {
+			t.Fatalf("string index %d out of range", i)
+		}
+		return strs[i]
+	}
+	if len(strs) == 0 || strs[0] != "" {
+		t.Fatalf("string table %q does not start with the empty string", strs)
+	}
+
+	var types []string
+	funcs := make(map[uint64]string) // function ID -> name
+	locs := make(map[uint64]uint64)  // location ID -> function ID
+	var samples [][]protoField
+	defaultType := ""
+	for _, f := range fields {
+		switch f.tag {
+		case 1: // sample_type
+			var typ, unit string
+			for _, vf := range mustDecodeProto(t, f.bytes) {
+				switch vf.tag {
+				case 1:
+					typ = str(vf.value)
+				case 2:
+					unit = str(vf.value)
+				}
+			}
+			types = append(types, typ+"/"+unit)
+		case 2: // sample
+			samples = append(samples, mustDecodeProto(t, f.bytes))
+		case 4: // location
+			var id, fn uint64
+			for _, lf := range mustDecodeProto(t, f.bytes) {
+				switch lf.tag {
+				case 1:
+					id = lf.value
+				case 4:
+					for _, line := range mustDecodeProto(t, lf.bytes) {
+						if line.tag == 1 {
+							fn = line.value
+						}
+					}
+				}
+			}
+			locs[id] = fn
+		case 5: // function
+			var id uint64
+			var fname string
+			for _, ff := range mustDecodeProto(t, f.bytes) {
+				switch ff.tag {
+				case 1:
+					id = ff.value
+				case 2:
+					fname = str(ff.value)
+				}
+			}
+			funcs[id] = fname
+		case 14: // default_sample_type
+			defaultType = str(f.value)
+		}
+	}
+	wantTypes := "[contentions/count delay/nanoseconds acquisitions/count hold/nanoseconds spins/count starvations/count]"
+	if fmt.Sprint(types) != wantTypes {
+		t.Fatalf("sample types %v, want %s", types, wantTypes)
+	}
+	if defaultType != "delay" {
+		t.Errorf("default sample type %q, want %q", defaultType, "delay")
+	}
+
+	found := false
+	for _, sample := range samples {
+		var values []int64
+		var loc uint64
+		var label string
+		for _, sf := range sample {
+			switch sf.tag {
+			case 1:
+				loc = sf.value
+			case 2:
+				values = append(values, int64(sf.value))
+			case 3:
+				var key, val string
+				for _, lf := range mustDecodeProto(t, sf.bytes) {
+					switch lf.tag {
+					case 1:
+						key = str(lf.value)
+					case 2:
+						val = str(lf.value)
+					}
+				}
+				if key == "mutex" {
+					label = val
+				}
+			}
+		}
+		if label != name {
+			continue
+		}
+		found = true
+		if fn := funcs[locs[loc]]; fn != name {
+			t.Errorf("sample location %d names function %q, want %q", loc, fn, name)
+		}
+		// The statistics do not change, since m is no longer used.
+		wantValues := []int64{want.Contended, want.WaitTime, want.Acquired, want.HoldTime, want.Spins, want.Starvations}
+		if fmt.Sprint(values) != fmt.Sprint(wantValues) {
+			t.Errorf("sample values %v, want %v", values, wantValues)
+		}
+	}
+	if !found {
+		t.Fatalf("no sample labeled mutex=%q", name)
+	}
+}
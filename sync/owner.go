@@ -0,0 +1,265 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Owner tracking.
+//
+// When the package is built with -tags syncdebug, Mutex and RWMutex record
+// which goroutine holds them, and where it acquired them, and turn the
+// following misuse into a fatal error that prints the stacks of both the
+// offending goroutine and the holder:
+//
+//   - locking a Mutex, or an RWMutex for writing, that the same goroutine
+//     already holds, which would deadlock;
+//   - RLock of an RWMutex that the same goroutine holds for reading or
+//     writing, and Lock of one it holds for reading;
+//   - unlocking from a goroutine other than the one that locked.
+//
+// The last is allowed by the documentation of Mutex and RWMutex, so
+// programs that hand locks over between goroutines cannot use this mode.
+// The owners are kept in tables keyed by the address of the lock, which
+// leaves the layout of the lock types unchanged; the cost of a stack trace
+// per acquisition makes the mode suitable for tests only.
+//
+// 持有者跟踪。
+//
+// 当使用-tags syncdebug构建本包时，Mutex和RWMutex会记录哪个goroutine持有它们以及在哪里获取的，
+// 并将下列误用变为致命错误，同时打印出违规goroutine和持有者两者的栈：
+//
+//   - 锁定同一个goroutine已经持有的Mutex或者已经为写持有的RWMutex，这会导致死锁；
+//   - 对同一个goroutine已经为读或写持有的RWMutex调用RLock，以及对已经为读持有的RWMutex调用Lock；
+//   - 从加锁的goroutine以外的goroutine解锁。
+//
+// 最后一种在Mutex和RWMutex的文档中是允许的，因此在goroutine之间交接锁的程序不能使用这个模式。
+// 持有者保存在以锁的地址为键的表中，锁类型的内存布局保持不变；每次获取锁都要生成一次栈跟踪，
+// 因此该模式只适合在测试中使用。
+
+// owners holds the owners of the locks that are currently held.
+// owners 保存当前被持有的锁的持有者。
+var owners struct {
+	mu      spinLock
+	mutex   map[*Mutex]*lockOwner
+	rwmutex map[*RWMutex]*lockOwner
+}
+
+// spinLock is a minimal lock for the bookkeeping of the debugging modes,
+// which cannot use a Mutex without tracking it as well.
+// spinLock 是用于调试模式记账的最小化锁，调试模式不能使用Mutex，否则也会跟踪它。
+type spinLock struct {
+	locked atomic.Bool
+}
+
+func (l *spinLock) lock() {
+	for !l.locked.CompareAndSwap(false, true) {
+		runtime.Gosched()
+	}
+}
+
+func (l *spinLock) unlock() {
+	l.locked.Store(false)
+}
+
+// lockOwner describes the holders of a lock.
+// lockOwner 描述一个锁的持有者。
+type lockOwner struct {
+	writer  lockSite             // holder of the lock for writing; goid 0 if none
+	readers map[uint64]*readHold // holders of read locks, by goroutine ID
+}
+
+// readHold describes the read locks held by a goroutine.
+// readHold 描述一个goroutine持有的读锁。
+type readHold struct {
+	site lockSite // where the first one was acquired
+	n    int      // number of read locks held; only TryRLock can make it > 1
+}
+
+// A lockSite is a goroutine and its stack at a call to a lock method.
+// lockSite 是调用锁方法时的goroutine及其栈。
+type lockSite struct {
+	goid  uint64
+	stack []byte
+}
+
+// currentSite returns the lockSite of the caller.
+// currentSite 返回调用者的lockSite。
+func currentSite() lockSite {
+	buf := make([]byte, 1024)
+	for {
+		n := runtime.Stack(buf, false)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	return lockSite{goid: parseGoid(buf), stack: buf}
+}
+
+// ownerFatal reports misuse of a lock detected at cur, which conflicts
+// with how the lock was acquired at held.
+// ownerFatal 报告在cur处检测到的锁的误用，它与在held处获取锁的方式相冲突。
+func ownerFatal(msg string, cur, held lockSite) {
+	print("sync: ", msg, "\n\nat:\n", string(cur.stack), "\nlock acquired at:\n", string(held.stack), "\n")
+	fatal("sync: " + msg)
+}
+
+// debugLocking checks that the caller does not hold m yet and returns its
+// lockSite, to be passed to debugLocked once m is acquired.
+// debugLocking 检查调用者尚未持有m，并返回其lockSite，在获取m之后传给debugLocked。
+func (m *Mutex) debugLocking() lockSite {
+	cur := currentSite()
+	owners.mu.lock()
+	o := owners.mutex[m]
+	owners.mu.unlock()
+	if o != nil && o.writer.goid == cur.goid {
+		ownerFatal("Lock of Mutex already locked by the same goroutine", cur, o.writer)
+	}
+	return cur
+}
+
+// debugLocked records that m was acquired at site.
+// debugLocked 记录m是在site处获取的。
+func (m *Mutex) debugLocked(site lockSite) {
+	owners.mu.lock()
+	if owners.mutex == nil {
+		owners.mutex = make(map[*Mutex]*lockOwner)
+	}
+	owners.mutex[m] = &lockOwner{writer: site}
+	owners.mu.unlock()
+}
+
+// debugUnlock checks that the caller holds m and forgets its owner.
+// A Mutex locked without tracking, such as by the helper goroutine of
+// LockContext, has no owner to check.
+// debugUnlock 检查调用者持有m，并忘记其持有者。没有经过跟踪而被锁定的Mutex（例如由LockContext的辅助goroutine锁定）没有可检查的持有者。
+func (m *Mutex) debugUnlock() {
+	owners.mu.lock()
+	o := owners.mutex[m]
+	delete(owners.mutex, m)
+	owners.mu.unlock()
+	if o != nil && o.writer.goid != goid() {
+		ownerFatal("Unlock of Mutex locked by another goroutine", currentSite(), o.writer)
+	}
+}
+
+// rwOwner returns the owner record of rw, creating it if needed.
+// Called with owners.mu held.
+// rwOwner 返回rw的持有者记录，必要时创建它。调用时必须持有owners.mu。
+func (rw *RWMutex) rwOwner() *lockOwner {
+	o := owners.rwmutex[rw]
+	if o == nil {
+		if owners.rwmutex == nil {
+			owners.rwmutex = make(map[*RWMutex]*lockOwner)
+		}
+		o = &lockOwner{readers: make(map[uint64]*readHold)}
+		owners.rwmutex[rw] = o
+	}
+	return o
+}
+
+// forget drops the owner record of rw once nobody holds it.
+// Called with owners.mu held.
+// forget 在rw不再被任何goroutine持有时丢弃其持有者记录。调用时必须持有owners.mu。
+func (rw *RWMutex) forget(o *lockOwner) {
+	if o.writer.goid == 0 && len(o.readers) == 0 {
+		delete(owners.rwmutex, rw)
+	}
+}
+
+// debugLocking checks that the caller holds rw neither for reading nor
+// for writing, and returns its lockSite. The caller is about to call
+// method, RLock or Lock.
+// debugLocking 检查调用者既没有为读也没有为写持有rw，并返回其lockSite。调用者即将调用method，即RLock或Lock。
+func (rw *RWMutex) debugLocking(method string) lockSite {
+	cur := currentSite()
+	owners.mu.lock()
+	o := owners.rwmutex[rw]
+	var w lockSite
+	var r *readHold
+	if o != nil {
+		w = o.writer
+		r = o.readers[cur.goid]
+	}
+	owners.mu.unlock()
+	if w.goid == cur.goid {
+		ownerFatal(method+" of RWMutex already locked for writing by the same goroutine", cur, w)
+	}
+	if r != nil {
+		ownerFatal(method+" of RWMutex already locked for reading by the same goroutine", cur, r.site)
+	}
+	return cur
+}
+
+// debugRLocked records that rw was acquired for reading at site.
+// debugRLocked 记录rw是在site处为读获取的。
+func (rw *RWMutex) debugRLocked(site lockSite) {
+	owners.mu.lock()
+	o := rw.rwOwner()
+	if r := o.readers[site.goid]; r != nil {
+		r.n++
+	} else {
+		o.readers[site.goid] = &readHold{site: site, n: 1}
+	}
+	owners.mu.unlock()
+}
+
+// debugRUnlock checks that the caller holds rw for reading and forgets it.
+// debugRUnlock 检查调用者为读持有rw，并忘记它。
+func (rw *RWMutex) debugRUnlock() {
+	id := goid()
+	owners.mu.lock()
+	o := owners.rwmutex[rw]
+	if o == nil {
+		owners.mu.unlock()
+		return
+	}
+	if r := o.readers[id]; r != nil {
+		if r.n--; r.n == 0 {
+			delete(o.readers, id)
+			rw.forget(o)
+		}
+		owners.mu.unlock()
+		return
+	}
+	var held lockSite
+	for _, r := range o.readers {
+		held = r.site
+		break
+	}
+	owners.mu.unlock()
+	if held.goid != 0 {
+		ownerFatal("RUnlock of RWMutex locked for reading by another goroutine", currentSite(), held)
+	}
+}
+
+// debugLocked records that rw was acquired for writing at site.
+// debugLocked 记录rw是在site处为写获取的。
+func (rw *RWMutex) debugLocked(site lockSite) {
+	owners.mu.lock()
+	rw.rwOwner().writer = site
+	owners.mu.unlock()
+}
+
+// debugUnlock checks that the caller holds rw for writing and forgets it.
+// debugUnlock 检查调用者为写持有rw，并忘记它。
+func (rw *RWMutex) debugUnlock() {
+	owners.mu.lock()
+	o := owners.rwmutex[rw]
+	var w lockSite
+	if o != nil {
+		w = o.writer
+		o.writer = lockSite{}
+		rw.forget(o)
+	}
+	owners.mu.unlock()
+	if w.goid != 0 && w.goid != goid() {
+		ownerFatal("Unlock of RWMutex locked by another goroutine", currentSite(), w)
+	}
+}
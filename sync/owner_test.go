@@ -0,0 +1,210 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build syncdebug
+
+package sync_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	. "sync"
+	"testing"
+)
+
+var ownerMisuseTests = []struct {
+	name  string
+	f     func()
+	msg   string
+	stack bool // whether the holder's stack is printed
+}{
+	{
+		"Mutex.Unlock by another goroutine",
+		func() {
+			var m Mutex
+			m.Lock()
+			inGoroutine(m.Unlock)
+		},
+		"sync: Unlock of Mutex locked by another goroutine",
+		true,
+	},
+	{
+		"Mutex.Unlock twice",
+		func() {
+			var m Mutex
+			m.Lock()
+			m.Unlock()
+			m.Unlock()
+		},
+		"sync: unlock of unlocked mutex",
+		false,
+	},
+	{
+		"Mutex.Lock recursive",
+		func() {
+			var m Mutex
+			m.Lock()
+			m.Lock()
+		},
+		"sync: Lock of Mutex already locked by the same goroutine",
+		true,
+	},
+	{
+		"Mutex.LockContext recursive",
+		func() {
+			var m Mutex
+			m.Lock()
+			m.LockTimeout(1e9)
+		},
+		"sync: Lock of Mutex already locked by the same goroutine",
+		true,
+	},
+	{
+		"PolicyMutex.Lock recursive with FairnessFIFO",
+		func() {
+			m := NewPolicyMutex(FairnessFIFO, 0)
+			m.Lock()
+			m.Lock()
+		},
+		"sync: Lock of Mutex already locked by the same goroutine",
+		true,
+	},
+	{
+		"PolicyMutex.Unlock by another goroutine with FairnessFIFO",
+		func() {
+			m := NewPolicyMutex(FairnessFIFO, 0)
+			m.Lock()
+			inGoroutine(m.Unlock)
+		},
+		"sync: Unlock of Mutex locked by another goroutine",
+		true,
+	},
+	{
+		"RWMutex.Unlock by another goroutine",
+		func() {
+			var rw RWMutex
+			rw.Lock()
+			inGoroutine(rw.Unlock)
+		},
+		"sync: Unlock of RWMutex locked by another goroutine",
+		true,
+	},
+	{
+		"RWMutex.Unlock twice",
+		func() {
+			var rw RWMutex
+			rw.Lock()
+			rw.Unlock()
+			rw.Unlock()
+		},
+		"sync: Unlock of unlocked RWMutex",
+		false,
+	},
+	{
+		"RWMutex.Lock recursive",
+		func() {
+			var rw RWMutex
+			rw.Lock()
+			rw.Lock()
+		},
+		"sync: Lock of RWMutex already locked for writing by the same goroutine",
+		true,
+	},
+	{
+		"RWMutex.RUnlock by another goroutine",
+		func() {
+			var rw RWMutex
+			rw.RLock()
+			inGoroutine(rw.RUnlock)
+		},
+		"sync: RUnlock of RWMutex locked for reading by another goroutine",
+		true,
+	},
+	{
+		"RWMutex.RLock recursive",
+		func() {
+			var rw RWMutex
+			rw.RLock()
+			rw.RLock()
+		},
+		"sync: RLock of RWMutex already locked for reading by the same goroutine",
+		true,
+	},
+	{
+		"RWMutex.Lock while reading",
+		func() {
+			var rw RWMutex
+			rw.RLock()
+			rw.Lock()
+		},
+		"sync: Lock of RWMutex already locked for reading by the same goroutine",
+		true,
+	},
+}
+
+func init() {
+	if len(os.Args) == 3 && os.Args[1] == "TESTOWNERMISUSE" {
+		for _, test := range ownerMisuseTests {
+			if test.name == os.Args[2] {
+				func() {
+					defer func() { recover() }()
+					test.f()
+				}()
+				fmt.Printf("test completed\n")
+				os.Exit(0)
+			}
+		}
+		fmt.Printf("unknown test\n")
+		os.Exit(0)
+	}
+}
+
+// TestOwnerMisuse runs each misuse in a child process, since the errors
+// are fatal.
+func TestOwnerMisuse(t *testing.T) {
+	for _, test := range ownerMisuseTests {
+		out, err := exec.Command(os.Args[0], "TESTOWNERMISUSE", test.name).CombinedOutput()
+		if err == nil || !strings.Contains(string(out), "fatal error: "+test.msg) {
+			t.Errorf("%s: did not find failure with message about %q: %v\n%s\n", test.name, test.msg, err, out)
+			continue
+		}
+		if test.stack && !strings.Contains(string(out), "lock acquired at:") {
+			t.Errorf("%s: holder's stack missing:\n%s", test.name, out)
+		}
+	}
+}
+
+// TestOwnerCorrectUse checks that correct uses pass, including TryLock and
+// several read locks of different goroutines.
+func TestOwnerCorrectUse(t *testing.T) {
+	var m Mutex
+	m.Lock()
+	m.Unlock()
+	if !m.TryLock() {
+		t.Fatal("TryLock failed")
+	}
+	m.Unlock()
+
+	for _, p := range []FairnessPolicy{FairnessDefault, FairnessFIFO} {
+		m := NewPolicyMutex(p, 0)
+		m.Lock()
+		m.Unlock()
+		if !m.TryLock() {
+			t.Fatal("TryLock of PolicyMutex failed")
+		}
+		m.Unlock()
+	}
+
+	var rw RWMutex
+	rw.RLock()
+	inGoroutine(func() {
+		rw.RLock()
+		rw.RUnlock()
+	})
+	rw.RUnlock()
+	rw.Lock()
+	rw.Unlock()
+}
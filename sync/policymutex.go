@@ -0,0 +1,221 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import (
+	"sync/atomic"
+	"unsafe"
+
+	"internal/race"
+)
+
+// A FairnessPolicy decides the order in which a PolicyMutex is granted to
+// the goroutines waiting for it.
+// FairnessPolicy 决定PolicyMutex被授予等待它的goroutine的顺序。
+type FairnessPolicy int
+
+const (
+	// FairnessDefault is the policy of Mutex: a woken waiter competes with
+	// newly arriving goroutines, until one waits longer than the
+	// starvation threshold and switches the mutex to starvation mode, in
+	// which the lock is handed off to the waiters in FIFO order.
+	// FairnessDefault 是Mutex的策略：被唤醒的等待者与新到达的goroutine竞争，直到某个等待者的等待时间超过饥饿阈值，
+	// 将mutex切换到饥饿模式，在饥饿模式下锁按照FIFO顺序直接移交给等待者。
+	FairnessDefault FairnessPolicy = iota
+
+	// FairnessFIFO grants the lock strictly in the order in which the
+	// goroutines called Lock: a goroutine that finds the mutex locked
+	// takes a ticket, and Unlock hands the lock to the oldest ticket.
+	// It bounds the latency of every waiter, at the cost of a context
+	// switch for each contended handoff.
+	// FairnessFIFO 严格按照goroutine调用Lock的顺序授予锁：发现mutex已被锁定的goroutine领取一张票，
+	// Unlock把锁移交给最早的票。它限定了每个等待者的延迟，代价是每次有竞争的移交都需要一次上下文切换。
+	FairnessFIFO
+
+	// FairnessNoHandoff never switches to starvation mode: the lock always
+	// goes to whichever goroutine grabs it first, which favors the running
+	// ones. It gives the best throughput, but a waiter may starve.
+	// FairnessNoHandoff 从不切换到饥饿模式：锁总是归最先抢到它的goroutine所有，这有利于正在运行的goroutine。
+	// 它提供最好的吞吐量，但等待者可能会饥饿。
+	FairnessNoHandoff
+)
+
+// A PolicyMutex is a mutual exclusion lock whose fairness policy and
+// starvation threshold are chosen per instance by NewPolicyMutex. The
+// zero value is an unlocked mutex that behaves like a Mutex.
+// PolicyMutex 是一个互斥锁，其公平性策略和饥饿阈值由NewPolicyMutex为每个实例单独选择。
+// 零值是一个行为与Mutex相同的未锁定互斥锁。
+//
+// Like a Mutex, a locked PolicyMutex is not associated with a particular
+// goroutine, and it must not be copied after first use.
+// 和Mutex一样，被锁定的PolicyMutex不与特定的goroutine关联，并且在第一次使用后不能被复制。
+type PolicyMutex struct {
+	mu                  Mutex
+	policy              FairnessPolicy
+	starvationThreshold int64 // in nanoseconds; 0 means starvationThresholdNs
+
+	// With FairnessFIFO, mu only guards the fields below, and is held
+	// just long enough to update them, by lockQueue. The debugging modes
+	// track mu as the PolicyMutex itself with every policy.
+	// 使用FairnessFIFO时，mu只保护下面的字段，并且只在lockQueue更新它们期间被持有。
+	// 在所有策略下，调试模式都把mu当作PolicyMutex本身来跟踪。
+	locked  bool
+	waiters uint32     // tickets taken from queue and not yet served
+	queue   notifyList // the tickets of the waiters
+}
+
+// neverStarve is a starvation threshold that is never exceeded.
+// neverStarve 是一个永远不会被超过的饥饿阈值。
+const neverStarve = 1<<63 - 1
+
+// NewPolicyMutex returns an unlocked PolicyMutex that follows policy.
+// With FairnessDefault, a waiter switches the mutex to starvation mode
+// after starvationThreshold nanoseconds, or after the 1ms used by Mutex if
+// starvationThreshold is not positive; the other policies ignore it.
+// NewPolicyMutex 返回一个遵循policy的未锁定PolicyMutex。使用FairnessDefault时，等待者在starvationThreshold纳秒后
+// 将mutex切换到饥饿模式；如果starvationThreshold不是正数，则使用Mutex的1ms；其他策略会忽略它。
+func NewPolicyMutex(policy FairnessPolicy, starvationThreshold int64) *PolicyMutex {
+	if policy < FairnessDefault || policy > FairnessNoHandoff {
+		panic("sync: unknown FairnessPolicy")
+	}
+	m := &PolicyMutex{policy: policy}
+	if starvationThreshold > 0 {
+		m.starvationThreshold = starvationThreshold
+	}
+	return m
+}
+
+// Lock locks m. If the lock is already in use, the calling goroutine
+// blocks until it is granted the mutex according to the policy of m.
+// Lock 锁定m。如果锁已经被使用，调用者goroutine会阻塞，直到按照m的策略被授予该mutex。
+func (m *PolicyMutex) Lock() {
+	if debugOwners {
+		defer m.mu.debugLocked(m.mu.debugLocking())
+	}
+	if lockdepEnabled {
+		defer lockdepLocked(lockdepLocking(lockKey{p: unsafe.Pointer(&m.mu)}))
+	}
+	if m.policy == FairnessFIFO {
+		m.lockFIFO()
+		return
+	}
+	if atomic.CompareAndSwapInt32(&m.mu.state, 0, mutexLocked) {
+		if race.Enabled {
+			race.Acquire(unsafe.Pointer(&m.mu))
+		}
+		return
+	}
+	threshold := int64(starvationThresholdNs)
+	if m.policy == FairnessNoHandoff {
+		threshold = neverStarve
+	} else if m.starvationThreshold > 0 {
+		threshold = m.starvationThreshold
+	}
+	m.mu.lockSlow(nil, threshold)
+}
+
+// TryLock tries to lock m and reports whether it succeeded; see
+// Mutex.TryLock. With FairnessFIFO, it fails whenever there are waiters.
+// TryLock 尝试锁定m并报告是否成功；参见Mutex.TryLock。使用FairnessFIFO时，只要存在等待者它就会失败。
+func (m *PolicyMutex) TryLock() bool {
+	if m.policy != FairnessFIFO {
+		return m.mu.TryLock()
+	}
+	m.lockQueue()
+	ok := !m.locked
+	m.locked = true
+	m.unlockQueue()
+	if !ok {
+		return false
+	}
+	if race.Enabled {
+		race.Acquire(unsafe.Pointer(&m.queue))
+	}
+	if debugOwners {
+		m.mu.debugLocked(currentSite())
+	}
+	if lockdepEnabled {
+		lockdepLocked(lockdepSite(lockKey{p: unsafe.Pointer(&m.mu)}))
+	}
+	return true
+}
+
+// Unlock unlocks m, handing it to the next goroutine according to the
+// policy of m. It is a run-time error if m is not locked on entry to
+// Unlock.
+// Unlock 解锁m，并按照m的策略把它交给下一个goroutine。如果m在进入Unlock时没有被锁定，则会产生运行时错误。
+func (m *PolicyMutex) Unlock() {
+	if m.policy != FairnessFIFO {
+		m.mu.Unlock()
+		return
+	}
+	if debugOwners {
+		m.mu.debugUnlock()
+	}
+	if lockdepEnabled {
+		lockdepRelease(lockKey{p: unsafe.Pointer(&m.mu)})
+	}
+	if race.Enabled {
+		race.Release(unsafe.Pointer(&m.queue))
+	}
+	m.lockQueue()
+	if !m.locked {
+		m.unlockQueue()
+		fatal("sync: unlock of unlocked mutex")
+	}
+	if m.waiters > 0 {
+		// Hand the lock to the oldest ticket; locked stays set.
+		// 把锁移交给最早的票；locked保持为true
+		m.waiters--
+		runtime_notifyListNotifyOne(&m.queue)
+	} else {
+		m.locked = false
+	}
+	m.unlockQueue()
+}
+
+func (m *PolicyMutex) lockFIFO() {
+	m.lockQueue()
+	if !m.locked {
+		m.locked = true
+		m.unlockQueue()
+	} else {
+		// Tickets are taken and served under mu, so the notification
+		// of Unlock always goes to the oldest one, even if its owner
+		// has not started waiting yet.
+		// 票的领取和服务都在mu下进行，因此Unlock的通知总是发给最早的票，即使其持有者还没有开始等待
+		t := runtime_notifyListAdd(&m.queue)
+		m.waiters++
+		m.unlockQueue()
+		runtime_notifyListWait(&m.queue, t)
+	}
+	if race.Enabled {
+		race.Acquire(unsafe.Pointer(&m.queue))
+	}
+}
+
+// lockQueue locks mu to update the fields of FairnessFIFO, bypassing the
+// hooks of the debugging modes, which track mu as the PolicyMutex.
+// lockQueue 锁定mu以更新FairnessFIFO的字段，并绕过调试模式的钩子，调试模式把mu当作PolicyMutex来跟踪。
+func (m *PolicyMutex) lockQueue() {
+	if atomic.CompareAndSwapInt32(&m.mu.state, 0, mutexLocked) {
+		if race.Enabled {
+			race.Acquire(unsafe.Pointer(&m.mu))
+		}
+		return
+	}
+	m.mu.lockSlow(nil, starvationThresholdNs)
+}
+
+// unlockQueue unlocks mu after lockQueue.
+// unlockQueue 在lockQueue之后解锁mu。
+func (m *PolicyMutex) unlockQueue() {
+	if race.Enabled {
+		race.Release(unsafe.Pointer(&m.mu))
+	}
+	if new := atomic.AddInt32(&m.mu.state, -mutexLocked); new != 0 {
+		m.mu.unlockSlow(new)
+	}
+}
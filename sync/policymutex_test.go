@@ -0,0 +1,153 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync_test
+
+import (
+	. "sync"
+	"testing"
+	"time"
+)
+
+var fairnessPolicies = []FairnessPolicy{FairnessDefault, FairnessFIFO, FairnessNoHandoff}
+
+func fairnessName(p FairnessPolicy) string {
+	switch p {
+	case FairnessDefault:
+		return "Default"
+	case FairnessFIFO:
+		return "FIFO"
+	case FairnessNoHandoff:
+		return "NoHandoff"
+	}
+	return "unknown"
+}
+
+func TestPolicyMutex(t *testing.T) {
+	for _, p := range fairnessPolicies {
+		t.Run(fairnessName(p), func(t *testing.T) {
+			m := NewPolicyMutex(p, 1000)
+			if !m.TryLock() {
+				t.Fatal("TryLock of unlocked PolicyMutex failed")
+			}
+			if m.TryLock() {
+				t.Fatal("TryLock of locked PolicyMutex succeeded")
+			}
+			m.Unlock()
+
+			var l Locker = m
+			n := 0
+			var wg WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 1000; j++ {
+						l.Lock()
+						n++
+						l.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+			if n != 8*1000 {
+				t.Fatalf("n = %d, want %d", n, 8*1000)
+			}
+		})
+	}
+}
+
+// TestPolicyMutexFIFO checks that FairnessFIFO grants the lock in the order
+// of the calls to Lock.
+func TestPolicyMutexFIFO(t *testing.T) {
+	m := NewPolicyMutex(FairnessFIFO, 0)
+	m.Lock()
+	const n = 10
+	var order []int
+	done := make(chan bool)
+	for i := 0; i < n; i++ {
+		go func() {
+			m.Lock()
+			order = append(order, i)
+			m.Unlock()
+			done <- true
+		}()
+		deadline := time.Now().Add(5 * time.Second)
+		for PolicyMutexTickets(m) != uint32(i+1) {
+			if time.Now().After(deadline) {
+				t.Fatalf("%d waiters, want %d", PolicyMutexTickets(m), i+1)
+			}
+			time.Sleep(100 * time.Microsecond)
+		}
+	}
+	m.Unlock()
+	for i := 0; i < n; i++ {
+		<-done
+	}
+	for i, v := range order {
+		if v != i {
+			t.Fatalf("lock granted in order %v", order)
+		}
+	}
+	if !m.TryLock() {
+		t.Fatal("TryLock of unlocked PolicyMutex failed")
+	}
+	m.Unlock()
+}
+
+// starves keeps m locked while a waiter waits for it, unlocking and
+// relocking it every few milliseconds before the woken waiter can take
+// it, and reports whether m switches to starvation mode.
+func starves(m *PolicyMutex) bool {
+	mu := PolicyMutexMutex(m)
+	m.Lock()
+	defer m.Unlock()
+	stop := make(chan bool)
+	defer close(stop)
+	waiter := make(chan bool, 1)
+	start := func() {
+		go func() {
+			m.Lock()
+			m.Unlock()
+			select {
+			case waiter <- true:
+			case <-stop:
+			}
+		}()
+	}
+	start()
+	for i := 0; i < 50; i++ {
+		time.Sleep(2 * time.Millisecond)
+		if MutexStarving(mu) {
+			return true
+		}
+		m.Unlock()
+		m.Lock()
+		select {
+		case <-waiter:
+			// The waiter won; try again.
+			start()
+		default:
+		}
+	}
+	return false
+}
+
+func TestPolicyMutexNoHandoff(t *testing.T) {
+	if !starves(NewPolicyMutex(FairnessDefault, 1000)) {
+		t.Fatal("PolicyMutex with FairnessDefault did not switch to starvation mode")
+	}
+	if starves(NewPolicyMutex(FairnessNoHandoff, 1000)) {
+		t.Fatal("PolicyMutex with FairnessNoHandoff switched to starvation mode")
+	}
+}
+
+func TestNewPolicyMutexUnknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewPolicyMutex with an unknown policy did not panic")
+		}
+	}()
+	NewPolicyMutex(FairnessNoHandoff+1, 0)
+}
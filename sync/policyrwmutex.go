@@ -0,0 +1,284 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import (
+	"unsafe"
+
+	"internal/race"
+)
+
+// An RWPolicy decides whether readers or writers go first when both wait
+// for a PolicyRWMutex.
+// RWPolicy 决定当读者和写者都在等待PolicyRWMutex时谁先获得锁。
+type RWPolicy int
+
+const (
+	// RWPhaseFair alternates between readers and writers, which is what
+	// RWMutex does: once a writer waits, new readers wait too, and when
+	// the writer unlocks, all the readers waiting at that time go before
+	// the next writer. Neither side can starve: a writer waits for at most
+	// the readers already holding the lock and the writers ahead of it,
+	// and a reader waits for at most one writer.
+	// RWPhaseFair 在读者和写者之间交替，这也是RWMutex的做法：一旦有写者在等待，新的读者也要等待；
+	// 当写者解锁时，此时正在等待的所有读者都先于下一个写者获得锁。双方都不会饥饿：写者最多等待已经持有锁的读者
+	// 以及排在它前面的写者，读者最多等待一个写者。
+	RWPhaseFair RWPolicy = iota
+
+	// RWWriterPreferred lets readers in only when no writer holds or waits
+	// for the lock, and hands the lock from writer to writer as long as
+	// there are any. Writers cannot starve; readers can, if writers keep
+	// arriving.
+	// RWWriterPreferred 只有在没有写者持有或等待锁时才允许读者进入，并且只要还有写者就把锁在写者之间移交。
+	// 写者不会饥饿；如果写者不断到来，读者可能会饥饿。
+	RWWriterPreferred
+
+	// RWReaderPreferred lets readers in whenever no writer holds the lock,
+	// even if writers are waiting, and hands the lock to the waiting
+	// readers before any waiting writer. Readers cannot starve; writers
+	// can, if the read locks keep overlapping.
+	// RWReaderPreferred 只要没有写者持有锁就允许读者进入，即使有写者在等待；并且先把锁交给等待的读者，
+	// 然后才是等待的写者。读者不会饥饿；如果读锁的持有时间不断重叠，写者可能会饥饿。
+	RWReaderPreferred
+)
+
+// A PolicyRWMutex is a reader/writer mutual exclusion lock whose policy
+// between readers and writers is chosen per instance by NewPolicyRWMutex.
+// The zero value is an unlocked mutex with the RWPhaseFair policy.
+// PolicyRWMutex 是一个读写互斥锁，其在读者和写者之间的策略由NewPolicyRWMutex为每个实例单独选择。
+// 零值是一个使用RWPhaseFair策略的未锁定互斥锁。
+//
+// A PolicyRWMutex hands the lock over directly: when it is released to
+// waiting goroutines, they are granted it before they even run, so a
+// goroutine arriving in the meantime cannot take their turn. Within
+// readers and within writers, the lock is granted in order of arrival.
+// PolicyRWMutex 直接移交锁：当锁被释放给等待的goroutine时，它们在运行之前就被授予了锁，
+// 因此这期间到达的goroutine无法抢占它们的机会。在读者之间以及写者之间，锁按照到达的顺序授予。
+//
+// As with RWMutex, recursive read locking is prohibited, except with
+// RWReaderPreferred, and a PolicyRWMutex must not be copied after first
+// use.
+// 和RWMutex一样，禁止递归读锁定（RWReaderPreferred除外），并且PolicyRWMutex在第一次使用后不能被复制。
+type PolicyRWMutex struct {
+	mu     Mutex // guards the fields below; held only to update them
+	policy RWPolicy
+
+	readers        int  // number of goroutines holding the lock for reading
+	writer         bool // a goroutine holds the lock for writing
+	waitingReaders int  // tickets taken from readerQ and not yet served
+	waitingWriters int  // tickets taken from writerQ and not yet served
+	readerQ        notifyList
+	writerQ        notifyList
+}
+
+// Happens-before relationships are indicated to the race detector as for
+// RWMutex, with readerQ and writerQ in place of readerSem and writerSem.
+// Race synchronization is disabled while mu is held, so that mu does not
+// appear to synchronize the readers with each other.
+// happens-before关系以与RWMutex相同的方式指示给race检测器，用readerQ和writerQ代替readerSem和writerSem。
+// 持有mu期间禁用race同步，这样mu不会看起来在读者之间建立了同步。
+
+// NewPolicyRWMutex returns an unlocked PolicyRWMutex that follows policy.
+// NewPolicyRWMutex 返回一个遵循policy的未锁定PolicyRWMutex。
+func NewPolicyRWMutex(policy RWPolicy) *PolicyRWMutex {
+	if policy < RWPhaseFair || policy > RWReaderPreferred {
+		panic("sync: unknown RWPolicy")
+	}
+	return &PolicyRWMutex{policy: policy}
+}
+
+// RLock locks rw for reading. It blocks while a writer holds rw, and,
+// unless the policy is RWReaderPreferred, while a writer waits for it.
+// RLock 为读锁定rw。当有写者持有rw时它会阻塞；除非策略是RWReaderPreferred，有写者在等待rw时它也会阻塞。
+func (rw *PolicyRWMutex) RLock() {
+	if lockdepEnabled {
+		defer lockdepLocked(lockdepLocking(lockKey{p: unsafe.Pointer(rw), rw: true}))
+	}
+	if race.Enabled {
+		race.Disable()
+	}
+	rw.mu.Lock()
+	if rw.canRLock() {
+		rw.readers++
+		rw.mu.Unlock()
+	} else {
+		rw.waitingReaders++
+		t := runtime_notifyListAdd(&rw.readerQ)
+		rw.mu.Unlock()
+		// The lock is granted before the notification.
+		// 锁在通知之前就已经被授予
+		runtime_notifyListWait(&rw.readerQ, t)
+	}
+	if race.Enabled {
+		race.Enable()
+		race.Acquire(unsafe.Pointer(&rw.readerQ))
+	}
+}
+
+// TryRLock tries to lock rw for reading and reports whether it succeeded.
+// TryRLock 尝试为读锁定rw，并报告是否成功。
+func (rw *PolicyRWMutex) TryRLock() bool {
+	if race.Enabled {
+		race.Disable()
+	}
+	rw.mu.Lock()
+	ok := rw.canRLock()
+	if ok {
+		rw.readers++
+	}
+	rw.mu.Unlock()
+	if race.Enabled {
+		race.Enable()
+		if ok {
+			race.Acquire(unsafe.Pointer(&rw.readerQ))
+		}
+	}
+	if ok && lockdepEnabled {
+		lockdepLocked(lockdepSite(lockKey{p: unsafe.Pointer(rw), rw: true}))
+	}
+	return ok
+}
+
+// canRLock reports whether a new reader may take the lock.
+// Called with rw.mu held.
+// canRLock 报告新的读者是否可以获取锁。调用时必须持有rw.mu。
+func (rw *PolicyRWMutex) canRLock() bool {
+	return !rw.writer && (rw.waitingWriters == 0 || rw.policy == RWReaderPreferred)
+}
+
+// RUnlock undoes a single RLock call. It is a run-time error if rw is not
+// locked for reading on entry to RUnlock.
+// RUnlock 撤销一次RLock调用。如果rw在进入RUnlock时没有为读锁定，则会产生运行时错误。
+func (rw *PolicyRWMutex) RUnlock() {
+	if lockdepEnabled {
+		lockdepRelease(lockKey{p: unsafe.Pointer(rw), rw: true})
+	}
+	if race.Enabled {
+		race.ReleaseMerge(unsafe.Pointer(&rw.writerQ))
+		race.Disable()
+	}
+	rw.mu.Lock()
+	if rw.readers == 0 {
+		rw.mu.Unlock()
+		if race.Enabled {
+			race.Enable()
+		}
+		fatal("sync: RUnlock of unlocked PolicyRWMutex")
+	}
+	rw.readers--
+	if rw.readers == 0 && rw.waitingWriters > 0 {
+		rw.grantWriter()
+	}
+	rw.mu.Unlock()
+	if race.Enabled {
+		race.Enable()
+	}
+}
+
+// Lock locks rw for writing. It blocks until no goroutine holds rw, and
+// the policy lets the caller go before the other waiters.
+// Lock 为写锁定rw。它会阻塞，直到没有goroutine持有rw，并且策略允许调用者先于其他等待者获得锁。
+func (rw *PolicyRWMutex) Lock() {
+	if lockdepEnabled {
+		defer lockdepLocked(lockdepLocking(lockKey{p: unsafe.Pointer(rw), rw: true}))
+	}
+	if race.Enabled {
+		race.Disable()
+	}
+	rw.mu.Lock()
+	if !rw.writer && rw.readers == 0 {
+		// Nobody waits for a free lock.
+		// 空闲的锁没有等待者
+		rw.writer = true
+		rw.mu.Unlock()
+	} else {
+		rw.waitingWriters++
+		t := runtime_notifyListAdd(&rw.writerQ)
+		rw.mu.Unlock()
+		runtime_notifyListWait(&rw.writerQ, t)
+	}
+	if race.Enabled {
+		race.Enable()
+		race.Acquire(unsafe.Pointer(&rw.readerQ))
+		race.Acquire(unsafe.Pointer(&rw.writerQ))
+	}
+}
+
+// TryLock tries to lock rw for writing and reports whether it succeeded.
+// TryLock 尝试为写锁定rw，并报告是否成功。
+func (rw *PolicyRWMutex) TryLock() bool {
+	if race.Enabled {
+		race.Disable()
+	}
+	rw.mu.Lock()
+	ok := !rw.writer && rw.readers == 0
+	if ok {
+		rw.writer = true
+	}
+	rw.mu.Unlock()
+	if race.Enabled {
+		race.Enable()
+		if ok {
+			race.Acquire(unsafe.Pointer(&rw.readerQ))
+			race.Acquire(unsafe.Pointer(&rw.writerQ))
+		}
+	}
+	if ok && lockdepEnabled {
+		lockdepLocked(lockdepSite(lockKey{p: unsafe.Pointer(rw), rw: true}))
+	}
+	return ok
+}
+
+// Unlock unlocks rw for writing, handing it to the waiting readers or to
+// the next waiting writer according to the policy of rw. It is a run-time
+// error if rw is not locked for writing on entry to Unlock.
+// Unlock 解除rw的写锁定，并按照rw的策略把它交给等待的读者或者下一个等待的写者。
+// 如果rw在进入Unlock时没有为写锁定，则会产生运行时错误。
+func (rw *PolicyRWMutex) Unlock() {
+	if lockdepEnabled {
+		lockdepRelease(lockKey{p: unsafe.Pointer(rw), rw: true})
+	}
+	if race.Enabled {
+		race.Release(unsafe.Pointer(&rw.readerQ))
+		race.Release(unsafe.Pointer(&rw.writerQ))
+		race.Disable()
+	}
+	rw.mu.Lock()
+	if !rw.writer {
+		rw.mu.Unlock()
+		if race.Enabled {
+			race.Enable()
+		}
+		fatal("sync: Unlock of unlocked PolicyRWMutex")
+	}
+	rw.writer = false
+	if rw.waitingReaders > 0 && (rw.waitingWriters == 0 || rw.policy != RWWriterPreferred) {
+		rw.grantReaders()
+	} else if rw.waitingWriters > 0 {
+		rw.grantWriter()
+	}
+	rw.mu.Unlock()
+	if race.Enabled {
+		race.Enable()
+	}
+}
+
+// grantReaders gives the lock to all the waiting readers.
+// Called with rw.mu held and the lock free.
+// grantReaders 把锁交给所有等待的读者。调用时必须持有rw.mu并且锁是空闲的。
+func (rw *PolicyRWMutex) grantReaders() {
+	rw.readers = rw.waitingReaders
+	rw.waitingReaders = 0
+	runtime_notifyListNotifyAll(&rw.readerQ)
+}
+
+// grantWriter gives the lock to the oldest waiting writer.
+// Called with rw.mu held and the lock free.
+// grantWriter 把锁交给最早等待的写者。调用时必须持有rw.mu并且锁是空闲的。
+func (rw *PolicyRWMutex) grantWriter() {
+	rw.writer = true
+	rw.waitingWriters--
+	runtime_notifyListNotifyOne(&rw.writerQ)
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync_test
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	. "sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var rwPolicies = []RWPolicy{RWPhaseFair, RWWriterPreferred, RWReaderPreferred}
+
+func policyName(p RWPolicy) string {
+	switch p {
+	case RWPhaseFair:
+		return "PhaseFair"
+	case RWWriterPreferred:
+		return "WriterPreferred"
+	case RWReaderPreferred:
+		return "ReaderPreferred"
+	}
+	return fmt.Sprintf("RWPolicy(%d)", int(p))
+}
+
+func TestPolicyRWMutexExclusion(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	for _, p := range rwPolicies {
+		t.Run(policyName(p), func(t *testing.T) {
+			rw := NewPolicyRWMutex(p)
+			var active atomic.Int32 // readers, or -1 for a writer
+			var wg WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 1000; j++ {
+						if (i+j)%4 == 0 {
+							rw.Lock()
+							if !active.CompareAndSwap(0, -1) {
+								t.Error("writer admitted with other holders")
+							}
+							active.Store(0)
+							rw.Unlock()
+						} else {
+							rw.RLock()
+							if active.Add(1) <= 0 {
+								t.Error("reader admitted with a writer")
+							}
+							active.Add(-1)
+							rw.RUnlock()
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			if !rw.TryLock() {
+				t.Fatal("lock not free at the end")
+			}
+			rw.Unlock()
+		})
+	}
+}
+
+// TestPolicyRWMutexReaderPreference checks whether a new reader is let in
+// while a writer waits.
+func TestPolicyRWMutexReaderPreference(t *testing.T) {
+	for _, p := range rwPolicies {
+		rw := NewPolicyRWMutex(p)
+		rw.RLock()
+		locked := make(chan bool)
+		go func() {
+			rw.Lock()
+			locked <- true
+			rw.Unlock()
+		}()
+		waitForWriter()
+		if got, want := rw.TryRLock(), p == RWReaderPreferred; got != want {
+			t.Errorf("%s: TryRLock with a waiting writer = %v, want %v", policyName(p), got, want)
+		} else if got {
+			rw.RUnlock()
+		}
+		rw.RUnlock()
+		<-locked
+	}
+}
+
+// waitForWriter waits until a writer is blocked in Lock. Nothing else in
+// the test parks in a notifyList.
+func waitForWriter() {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if strings.Contains(string(buf[:n]), "sync.runtime_notifyListWait(") {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// starvationTimeout bounds how long a goroutine that cannot starve may
+// wait for a lock that is continuously in demand.
+const starvationTimeout = 5 * time.Second
+
+// TestPolicyRWMutexWriterStarvation runs readers whose read locks always
+// overlap, and checks that a writer still gets the lock under the
+// policies that promise it.
+func TestPolicyRWMutexWriterStarvation(t *testing.T) {
+	for _, p := range []RWPolicy{RWPhaseFair, RWWriterPreferred} {
+		t.Run(policyName(p), func(t *testing.T) {
+			rw := NewPolicyRWMutex(p)
+			stop := make(chan bool)
+			var wg WaitGroup
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for {
+						select {
+						case <-stop:
+							return
+						default:
+						}
+						rw.RLock()
+						time.Sleep(100 * time.Microsecond)
+						rw.RUnlock()
+					}
+				}()
+			}
+			defer func() {
+				close(stop)
+				wg.Wait()
+			}()
+			time.Sleep(10 * time.Millisecond)
+			for i := 0; i < 10; i++ {
+				if !lockWithin(rw.Lock, rw.Unlock, starvationTimeout) {
+					t.Fatalf("writer starved by readers")
+				}
+			}
+		})
+	}
+}
+
+// TestPolicyRWMutexReaderStarvation runs writers that keep the lock
+// continuously in demand, and checks that a reader still gets the lock
+// under the policies that promise it.
+func TestPolicyRWMutexReaderStarvation(t *testing.T) {
+	for _, p := range []RWPolicy{RWPhaseFair, RWReaderPreferred} {
+		t.Run(policyName(p), func(t *testing.T) {
+			rw := NewPolicyRWMutex(p)
+			stop := make(chan bool)
+			var wg WaitGroup
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for {
+						select {
+						case <-stop:
+							return
+						default:
+						}
+						rw.Lock()
+						time.Sleep(100 * time.Microsecond)
+						rw.Unlock()
+					}
+				}()
+			}
+			defer func() {
+				close(stop)
+				wg.Wait()
+			}()
+			time.Sleep(10 * time.Millisecond)
+			for i := 0; i < 10; i++ {
+				if !lockWithin(rw.RLock, rw.RUnlock, starvationTimeout) {
+					t.Fatalf("reader starved by writers")
+				}
+			}
+		})
+	}
+}
+
+// lockWithin calls lock, then unlock, in another goroutine, and reports
+// whether lock returned within d. If it did not, the goroutine is left
+// running, and unlocks once it gets the lock. Either way the lock is
+// released by the goroutine that took it.
+func lockWithin(lock, unlock func(), d time.Duration) bool {
+	locked := make(chan bool)
+	unlocked := make(chan bool)
+	go func() {
+		lock()
+		close(locked)
+		unlock()
+		close(unlocked)
+	}()
+	select {
+	case <-locked:
+		<-unlocked
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
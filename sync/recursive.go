@@ -0,0 +1,87 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import "sync/atomic"
+
+// A RecursiveMutex is a mutual exclusion lock that the goroutine holding
+// it may lock again; it is released once Unlock has been called as many
+// times as Lock. Unlike a Mutex, a locked RecursiveMutex belongs to the
+// goroutine that locked it, and it is a run-time panic for any other
+// goroutine to unlock it.
+// RecursiveMutex 是一个互斥锁，持有它的goroutine可以再次锁定它；当Unlock的调用次数与Lock相同时锁才被释放。
+// 与Mutex不同，被锁定的RecursiveMutex属于锁定它的goroutine，其他goroutine解锁它会引发运行时panic。
+//
+// Needing a recursive lock usually means that the invariants the lock
+// protects are not clear at the point of the inner call; prefer
+// restructuring the code around a Mutex where possible. Identifying the
+// calling goroutine makes every Lock and Unlock considerably slower than
+// those of a Mutex.
+// 需要递归锁通常意味着在内层调用处锁所保护的不变量并不清晰；尽可能围绕Mutex重构代码。
+// 识别调用者goroutine使得每次Lock和Unlock都比Mutex的慢得多。
+//
+// The zero value for a RecursiveMutex is an unlocked mutex.
+// A RecursiveMutex must not be copied after first use.
+// RecursiveMutex 的零值是一个未锁定的互斥锁。RecursiveMutex在第一次使用后不能被复制。
+//
+// The first call to Lock by a goroutine and its matching Unlock
+// synchronize like those of a Mutex; the race detector sees the same
+// relations. Nested calls establish none.
+// 一个goroutine对Lock的第一次调用及与之匹配的Unlock像Mutex的一样进行同步；竞态检测器看到的也是同样的关系。
+// 嵌套的调用不建立任何同步关系。
+type RecursiveMutex struct {
+	mu    Mutex
+	owner atomic.Uint64 // goroutine ID of the holder; 0 if none
+	depth int           // number of Locks not yet unlocked; only accessed by the holder
+}
+
+// Lock locks m. If the calling goroutine already holds m, Lock only
+// records one more level of locking; otherwise it blocks until m is
+// available.
+// Lock 锁定m。如果调用者goroutine已经持有m，Lock只是多记录一层加锁；否则阻塞直到m可用。
+func (m *RecursiveMutex) Lock() {
+	g := goid()
+	if m.owner.Load() == g {
+		m.depth++
+		return
+	}
+	m.mu.Lock()
+	m.owner.Store(g)
+	m.depth = 1
+}
+
+// TryLock tries to lock m and reports whether it succeeded. It always
+// succeeds if the calling goroutine already holds m.
+// TryLock 尝试锁定m并报告是否成功。如果调用者goroutine已经持有m，它总是成功。
+func (m *RecursiveMutex) TryLock() bool {
+	g := goid()
+	if m.owner.Load() == g {
+		m.depth++
+		return true
+	}
+	if !m.mu.TryLock() {
+		return false
+	}
+	m.owner.Store(g)
+	m.depth = 1
+	return true
+}
+
+// Unlock undoes one call to Lock, and unlocks m when it undoes the
+// outermost one. It panics if the calling goroutine does not hold m.
+// Unlock 撤销一次Lock调用，当撤销的是最外层的那次时解锁m。如果调用者goroutine没有持有m，它会panic。
+func (m *RecursiveMutex) Unlock() {
+	// The owner can only be the caller's ID if the caller stored it,
+	// so no other goroutine can be changing it concurrently.
+	// 只有调用者自己存入时owner才会是调用者的ID，因此不会有其他goroutine同时修改它
+	if m.owner.Load() != goid() {
+		panic("sync: unlock of RecursiveMutex not held by the calling goroutine")
+	}
+	if m.depth--; m.depth > 0 {
+		return
+	}
+	m.owner.Store(0)
+	m.mu.Unlock()
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync_test
+
+import (
+	. "sync"
+	"testing"
+)
+
+// tryLockElsewhere reports whether TryLock of m succeeds in another
+// goroutine, which unlocks m again if it does.
+func tryLockElsewhere(m *RecursiveMutex) bool {
+	var ok bool
+	inGoroutine(func() {
+		if ok = m.TryLock(); ok {
+			m.Unlock()
+		}
+	})
+	return ok
+}
+
+// panics calls f and reports whether it panicked.
+func panics(f func()) (panicked bool) {
+	defer func() { panicked = recover() != nil }()
+	f()
+	return false
+}
+
+func TestRecursiveMutex(t *testing.T) {
+	var m RecursiveMutex
+	const depth = 5
+	for i := 0; i < depth; i++ {
+		if i%2 == 0 {
+			m.Lock()
+		} else if !m.TryLock() {
+			t.Fatalf("TryLock at depth %d failed", i)
+		}
+	}
+	for i := depth; i > 0; i-- {
+		if tryLockElsewhere(&m) {
+			t.Fatalf("TryLock by another goroutine succeeded at depth %d", i)
+		}
+		m.Unlock()
+	}
+	if !tryLockElsewhere(&m) {
+		t.Fatal("TryLock by another goroutine failed after the outermost Unlock")
+	}
+}
+
+func TestRecursiveMutexUnlockByOther(t *testing.T) {
+	var m RecursiveMutex
+	m.Lock()
+	m.Lock()
+	var panicked bool
+	inGoroutine(func() { panicked = panics(m.Unlock) })
+	if !panicked {
+		t.Fatal("Unlock by another goroutine did not panic")
+	}
+	// The failed Unlock must not have changed the depth.
+	m.Unlock()
+	if tryLockElsewhere(&m) {
+		t.Fatal("RecursiveMutex released early")
+	}
+	m.Unlock()
+	if !tryLockElsewhere(&m) {
+		t.Fatal("RecursiveMutex not released")
+	}
+}
+
+func TestRecursiveMutexUnlockUnlocked(t *testing.T) {
+	var m RecursiveMutex
+	if !panics(m.Unlock) {
+		t.Fatal("Unlock of unlocked RecursiveMutex did not panic")
+	}
+	m.Lock()
+	m.Unlock()
+	if !panics(m.Unlock) {
+		t.Fatal("Unlock at depth zero did not panic")
+	}
+}
+
+func TestRecursiveMutexExclusion(t *testing.T) {
+	var m RecursiveMutex
+	n := 0
+	var wg WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				m.Lock()
+				m.Lock()
+				n++
+				m.Unlock()
+				n++
+				m.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if n != 8*500*2 {
+		t.Fatalf("n = %d, want %d", n, 8*500*2)
+	}
+}
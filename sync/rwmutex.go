@@ -83,6 +83,12 @@ const rwmutexMaxReaders = 1 << 30
 // documentation on the RWMutex type.
 // 它不应该用于递归读锁定；阻塞的Lock调用会排除新的读者获取锁。请参阅RWMutex类型的文档。
 func (rw *RWMutex) RLock() {
+	if debugOwners {
+		defer rw.debugRLocked(rw.debugLocking("RLock"))
+	}
+	if lockdepEnabled {
+		defer lockdepLocked(lockdepLocking(lockKey{p: unsafe.Pointer(rw), rw: true}))
+	}
 	if race.Enabled {
 		_ = rw.w.state
 		race.Disable()
@@ -126,6 +132,12 @@ func (rw *RWMutex) TryRLock() bool {
 				race.Enable()
 				race.Acquire(unsafe.Pointer(&rw.readerSem))
 			}
+			if debugOwners {
+				rw.debugRLocked(currentSite())
+			}
+			if lockdepEnabled {
+				lockdepLocked(lockdepSite(lockKey{p: unsafe.Pointer(rw), rw: true}))
+			}
 			return true
 		}
 	}
@@ -138,6 +150,12 @@ func (rw *RWMutex) TryRLock() bool {
 // RUnlock 撤消单个RLock调用；它不会影响其他同时reader。
 // 如果rw在进入RUnlock时没有被读取锁定，则为运行时错误。
 func (rw *RWMutex) RUnlock() {
+	if debugOwners {
+		rw.debugRUnlock()
+	}
+	if lockdepEnabled {
+		lockdepRelease(lockKey{p: unsafe.Pointer(rw), rw: true})
+	}
 	if race.Enabled {
 		_ = rw.w.state
 		race.ReleaseMerge(unsafe.Pointer(&rw.writerSem))
@@ -173,6 +191,12 @@ func (rw *RWMutex) rUnlockSlow(r int32) {
 // Lock 锁定rw进行写入。
 // 如果锁已经被锁定进行读取或写入，则Lock阻塞，直到锁可用。
 func (rw *RWMutex) Lock() {
+	if debugOwners {
+		defer rw.debugLocked(rw.debugLocking("Lock"))
+	}
+	if lockdepEnabled {
+		defer lockdepLocked(lockdepLocking(lockKey{p: unsafe.Pointer(rw), rw: true}))
+	}
 	if race.Enabled {
 		_ = rw.w.state
 		race.Disable()
@@ -227,6 +251,12 @@ func (rw *RWMutex) TryLock() bool {
 		race.Acquire(unsafe.Pointer(&rw.readerSem))
 		race.Acquire(unsafe.Pointer(&rw.writerSem))
 	}
+	if debugOwners {
+		rw.debugLocked(currentSite())
+	}
+	if lockdepEnabled {
+		lockdepLocked(lockdepSite(lockKey{p: unsafe.Pointer(rw), rw: true}))
+	}
 	return true
 }
 
@@ -240,6 +270,12 @@ func (rw *RWMutex) TryLock() bool {
 // 与Mutexes一样，锁定的RWMutex与特定的goroutine无关。一个goroutine可以RLock（Lock）一个RWMutex，
 // 然后安排另一个goroutine RUnlock（Unlock）它。
 func (rw *RWMutex) Unlock() {
+	if debugOwners {
+		rw.debugUnlock()
+	}
+	if lockdepEnabled {
+		lockdepRelease(lockKey{p: unsafe.Pointer(rw), rw: true})
+	}
 	if race.Enabled {
 		_ = rw.w.state
 		race.Release(unsafe.Pointer(&rw.readerSem))
@@ -0,0 +1,153 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import (
+	"unsafe"
+
+	"internal/race"
+)
+
+// An upgradable read lock is a read lock that holds rw.w, the mutex that
+// writers take first. Holding it excludes writers and other upgradable
+// readers, but not plain readers, since no writer can make readerCount
+// negative without rw.w. Upgrade then announces the pending writer and
+// waits for the plain readers exactly as Lock does after acquiring rw.w.
+//
+// 可升级读锁是一个持有rw.w（写者首先获取的互斥锁）的读锁。持有它会排除写者和其他可升级读者，
+// 但不排除普通读者，因为没有rw.w任何写者都无法使readerCount变为负数。之后Upgrade就像Lock在获取rw.w之后那样，
+// 宣告有一个待处理的写者并等待普通读者。
+
+// UpgradableRLock locks rw for reading, with the right to turn the read
+// lock into a write lock later by calling Upgrade. Only one goroutine at a
+// time holds an upgradable read lock; it coexists with plain readers, but
+// writers wait until it is released, by UpgradableRUnlock or by Upgrade
+// and then Unlock. A writer waiting for it does not exclude new readers.
+// UpgradableRLock 为读锁定rw，并保留之后通过调用Upgrade将读锁变为写锁的权利。同一时间只有一个goroutine持有可升级读锁；
+// 它与普通读者共存，但写者要等到它被释放（通过UpgradableRUnlock，或者通过Upgrade之后再Unlock）。
+// 等待它的写者不会排除新的读者。
+func (rw *RWMutex) UpgradableRLock() {
+	if debugOwners {
+		defer rw.debugRLocked(rw.debugLocking("UpgradableRLock"))
+	}
+	if lockdepEnabled {
+		defer lockdepLocked(lockdepLocking(lockKey{p: unsafe.Pointer(rw), rw: true}))
+	}
+	if race.Enabled {
+		_ = rw.w.state
+		race.Disable()
+	}
+	// Exclude writers and other upgradable readers.
+	// 排除写者和其他可升级读者
+	rw.w.Lock()
+	// No writer is pending while rw.w is held, so this never blocks.
+	// 持有rw.w期间没有待处理的写者，因此这里永远不会阻塞
+	rw.readerCount.Add(1)
+	if race.Enabled {
+		race.Enable()
+		race.Acquire(unsafe.Pointer(&rw.readerSem))
+	}
+}
+
+// UpgradableRUnlock undoes an UpgradableRLock call that was not followed
+// by Upgrade. It is a run-time error if rw is not locked for upgradable
+// reading on entry to UpgradableRUnlock.
+// UpgradableRUnlock 撤销一次之后没有调用Upgrade的UpgradableRLock调用。
+// 如果rw在进入UpgradableRUnlock时没有被可升级读锁定，则会产生运行时错误。
+func (rw *RWMutex) UpgradableRUnlock() {
+	if debugOwners {
+		rw.debugRUnlock()
+	}
+	if lockdepEnabled {
+		lockdepRelease(lockKey{p: unsafe.Pointer(rw), rw: true})
+	}
+	if race.Enabled {
+		_ = rw.w.state
+		race.ReleaseMerge(unsafe.Pointer(&rw.writerSem))
+		race.Disable()
+	}
+	if rw.readerCount.Add(-1) < 0 {
+		race.Enable()
+		fatal("sync: UpgradableRUnlock of unlocked RWMutex")
+	}
+	rw.w.Unlock()
+	if race.Enabled {
+		race.Enable()
+	}
+}
+
+// Upgrade turns the upgradable read lock held by the caller into a write
+// lock, without letting any writer in between. It blocks new readers and
+// waits for the current ones to release the lock, like Lock. The write
+// lock is released by Unlock, or turned back into a read lock by
+// Downgrade.
+// Upgrade 将调用者持有的可升级读锁变为写锁，期间不会让任何写者插入。它像Lock一样阻止新的读者，
+// 并等待当前的读者释放锁。写锁通过Unlock释放，或者通过Downgrade变回读锁。
+func (rw *RWMutex) Upgrade() {
+	if debugOwners {
+		rw.debugRUnlock()
+		defer rw.debugLocked(currentSite())
+	}
+	if race.Enabled {
+		_ = rw.w.state
+		race.Disable()
+	}
+	// Announce to readers there is a pending writer, and stop counting
+	// the caller as one of them.
+	// 通知reader有writer在等待，并且不再把调用者算作其中之一
+	r := rw.readerCount.Add(-1-rwmutexMaxReaders) + rwmutexMaxReaders
+	if r < 0 {
+		race.Enable()
+		fatal("sync: Upgrade of RWMutex not locked for upgradable reading")
+	}
+	// Wait for the other active readers.
+	// 等待其他活动的reader
+	if r != 0 && rw.readerWait.Add(r) != 0 {
+		runtime_SemacquireRWMutex(&rw.writerSem, false, 0)
+	}
+	if race.Enabled {
+		race.Enable()
+		race.Acquire(unsafe.Pointer(&rw.readerSem))
+		race.Acquire(unsafe.Pointer(&rw.writerSem))
+	}
+}
+
+// Downgrade turns the write lock held by the caller into a read lock,
+// without letting any writer in between. The readers that were waiting
+// for the write lock proceed along with the caller, which releases the
+// read lock by RUnlock. It is a run-time error if rw is not locked for
+// writing on entry to Downgrade.
+// Downgrade 将调用者持有的写锁变为读锁，期间不会让任何写者插入。等待写锁的读者与调用者一起继续执行，
+// 调用者通过RUnlock释放读锁。如果rw在进入Downgrade时没有为写锁定，则会产生运行时错误。
+func (rw *RWMutex) Downgrade() {
+	if debugOwners {
+		rw.debugUnlock()
+		defer rw.debugRLocked(currentSite())
+	}
+	if race.Enabled {
+		_ = rw.w.state
+		race.Release(unsafe.Pointer(&rw.readerSem))
+		race.Disable()
+	}
+	// Announce to readers there is no active writer, counting the
+	// caller as one of the readers.
+	// 通知reader已经没有活动的writer，并把调用者算作其中之一
+	r := rw.readerCount.Add(rwmutexMaxReaders + 1)
+	if r > rwmutexMaxReaders {
+		race.Enable()
+		fatal("sync: Downgrade of unlocked RWMutex")
+	}
+	// Unblock the readers that were waiting, which excludes the caller.
+	// 解除等待的reader的阻塞，其中不包括调用者
+	for i := 0; i < int(r)-1; i++ {
+		runtime_Semrelease(&rw.readerSem, false, 0)
+	}
+	// Allow other writers to proceed.
+	// 允许其他writer继续竞争锁
+	rw.w.Unlock()
+	if race.Enabled {
+		race.Enable()
+	}
+}
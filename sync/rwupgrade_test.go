@@ -0,0 +1,190 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync_test
+
+import (
+	. "sync"
+	"testing"
+	"time"
+)
+
+// stillBlocked reports whether done is not closed within a short time.
+func stillBlocked(done <-chan bool) bool {
+	select {
+	case <-done:
+		return false
+	case <-time.After(20 * time.Millisecond):
+		return true
+	}
+}
+
+// lockedElsewhere reports whether TryRLock and TryLock of rw succeed in
+// another goroutine, which releases the locks it gets.
+func lockedElsewhere(rw *RWMutex) (read, write bool) {
+	inGoroutine(func() {
+		if read = rw.TryRLock(); read {
+			rw.RUnlock()
+		}
+		if write = rw.TryLock(); write {
+			rw.Unlock()
+		}
+	})
+	return read, write
+}
+
+// TestUpgradableRLock checks that an upgradable reader coexists with
+// readers but excludes writers and other upgradable readers.
+func TestUpgradableRLock(t *testing.T) {
+	var rw RWMutex
+	rw.UpgradableRLock()
+	second := make(chan bool)
+	go func() {
+		rw.UpgradableRLock()
+		close(second)
+		rw.UpgradableRUnlock()
+	}()
+	if !stillBlocked(second) {
+		t.Fatal("second UpgradableRLock did not block")
+	}
+	if read, write := lockedElsewhere(&rw); !read || write {
+		t.Fatalf("TryRLock, TryLock with an upgradable reader = %v, %v, want true, false", read, write)
+	}
+	rw.UpgradableRUnlock()
+	<-second
+	if read, write := lockedElsewhere(&rw); !read || !write {
+		t.Fatalf("TryRLock, TryLock after UpgradableRUnlock = %v, %v, want true, true", read, write)
+	}
+}
+
+// TestUpgrade checks that Upgrade waits for the current readers and then
+// excludes readers and writers.
+func TestUpgrade(t *testing.T) {
+	var rw RWMutex
+	readLocked, releaseRead := make(chan bool), make(chan bool)
+	go func() {
+		rw.RLock()
+		readLocked <- true
+		<-releaseRead
+		rw.RUnlock()
+	}()
+	<-readLocked
+
+	upgraded, unlock, unlocked := make(chan bool), make(chan bool), make(chan bool)
+	go func() {
+		rw.UpgradableRLock()
+		rw.Upgrade()
+		close(upgraded)
+		<-unlock
+		rw.Unlock()
+		close(unlocked)
+	}()
+	if !stillBlocked(upgraded) {
+		t.Fatal("Upgrade did not wait for the reader")
+	}
+	close(releaseRead)
+	<-upgraded
+	if read, write := lockedElsewhere(&rw); read || write {
+		t.Fatalf("TryRLock, TryLock after Upgrade = %v, %v, want false, false", read, write)
+	}
+	close(unlock)
+	<-unlocked
+	if read, write := lockedElsewhere(&rw); !read || !write {
+		t.Fatalf("TryRLock, TryLock after Unlock = %v, %v, want true, true", read, write)
+	}
+}
+
+// TestDowngrade checks that Downgrade lets the waiting readers in while
+// keeping the waiting writers out.
+func TestDowngrade(t *testing.T) {
+	var rw RWMutex
+	locked, downgrade, downgraded, runlock := make(chan bool), make(chan bool), make(chan bool), make(chan bool)
+	go func() {
+		rw.Lock()
+		close(locked)
+		<-downgrade
+		rw.Downgrade()
+		close(downgraded)
+		<-runlock
+		rw.RUnlock()
+	}()
+	<-locked
+
+	writer := make(chan bool)
+	go func() {
+		rw.Lock()
+		close(writer)
+		rw.Unlock()
+	}()
+	const n = 3
+	readers := make(chan bool, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			rw.RLock()
+			readers <- true
+			rw.RUnlock()
+		}()
+	}
+	if !stillBlocked(readers) {
+		t.Fatal("reader got in while the lock was held for writing")
+	}
+
+	close(downgrade)
+	<-downgraded
+	for i := 0; i < n; i++ {
+		select {
+		case <-readers:
+		case <-time.After(starvationTimeout):
+			t.Fatal("waiting reader not let in by Downgrade")
+		}
+	}
+	if !stillBlocked(writer) {
+		t.Fatal("writer got in after Downgrade")
+	}
+	if _, write := lockedElsewhere(&rw); write {
+		t.Fatal("TryLock after Downgrade succeeded")
+	}
+	close(runlock)
+	<-writer
+}
+
+func TestUpgradeStress(t *testing.T) {
+	var rw RWMutex
+	x := 0
+	var wg WaitGroup
+	for i := 0; i < 12; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 300; j++ {
+				switch (i + j) % 4 {
+				case 0:
+					rw.UpgradableRLock()
+					_ = x
+					rw.Upgrade()
+					x++
+					rw.Downgrade()
+					_ = x
+					rw.RUnlock()
+				case 1:
+					rw.UpgradableRLock()
+					_ = x
+					rw.UpgradableRUnlock()
+				case 2:
+					rw.Lock()
+					x++
+					rw.Unlock()
+				case 3:
+					rw.RLock()
+					_ = x
+					rw.RUnlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if read, write := lockedElsewhere(&rw); !read || !write {
+		t.Fatalf("TryRLock, TryLock at the end = %v, %v, want true, true", read, write)
+	}
+}